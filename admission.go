@@ -0,0 +1,66 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Semaphore is a view of the in-use limit of a pool set with WithMaxInUse as
+// a semaphore, so request admission control and object checkout share one
+// limit: every acquired slot and every checked out object takes one of the
+// same units. Without an in-use limit, every Acquire succeeds at once.
+type Semaphore struct {
+	p *Pool
+}
+
+// Semaphore returns the semaphore view of the in-use limit of the pool.
+func (p *Pool) Semaphore() *Semaphore {
+	return &Semaphore{p: p}
+}
+
+// Acquire acquires a slot, waiting until one is free, ctx is done or the pool
+// is drained, in which case it fails with ErrPoolClosed.
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if atomic.LoadInt32(&s.p.state) != stateOpen {
+		return ErrPoolClosed
+	}
+	if s.p.config().maxInUse <= 0 {
+		return nil
+	}
+	return s.p.inUse.acquire(ctx, 1)
+}
+
+// TryAcquire acquires a slot without waiting and reports whether it
+// succeeded.
+func (s *Semaphore) TryAcquire() bool {
+	if atomic.LoadInt32(&s.p.state) != stateOpen {
+		return false
+	}
+	return s.p.config().maxInUse <= 0 || s.p.inUse.tryAcquire(1)
+}
+
+// Release releases a slot acquired with Acquire or TryAcquire.
+func (s *Semaphore) Release() {
+	if s.p.config().maxInUse > 0 {
+		s.p.inUse.release(1)
+	}
+}
+
+// Get retrieves an object in the slot acquired by the caller, without taking
+// another one. The slot then belongs to the object and is released by its Put
+// or Discard instead of Release; if Get fails the caller still holds it.
+func (s *Semaphore) Get(ctx context.Context) (interface{}, error) {
+	return s.p.getContext(ctx, getOptions{acquired: true})
+}
+
+// Available returns the number of free slots, or -1 without an in-use limit.
+func (s *Semaphore) Available() int {
+	cfg := s.p.config()
+	if cfg.maxInUse <= 0 {
+		return -1
+	}
+	return max(cfg.maxInUse-int(s.p.inUse.held()), 0)
+}
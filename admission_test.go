@@ -0,0 +1,98 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestSemaphoreView tests that admission slots and checked out objects share the in-use limit.
+func TestSemaphoreView(t *testing.T) {
+	p := NewPool(func() interface{} { return new(int) }, WithMaxInUse(2, true))
+	sem := p.Semaphore()
+
+	obj := p.Get()
+	if err := sem.Acquire(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := sem.Available(); n != 0 {
+		t.Errorf("Expected no free slot, got %d", n)
+	}
+	if sem.TryAcquire() {
+		t.Error("Expected TryAcquire to fail at the limit")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := sem.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Acquire to wait for a slot, got %v", err)
+	}
+	if _, err := p.GetContext(ctx); err == nil {
+		t.Error("Expected Get to share the exhausted limit")
+	}
+
+	p.Put(obj)
+	if !sem.TryAcquire() {
+		t.Fatal("Expected the slot of the returned object to be free")
+	}
+	sem.Release()
+
+	// Converting an acquired slot into an object does not take another one
+	held, err := sem.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := sem.Available(); n != 1 {
+		t.Errorf("Expected 1 free slot, got %d", n)
+	}
+	p.Put(held)
+	if n := sem.Available(); n != 2 {
+		t.Errorf("Expected Put to release the slot, got %d free", n)
+	}
+}
+
+// TestSemaphoreViewUnbounded tests the view of a pool without an in-use limit.
+func TestSemaphoreViewUnbounded(t *testing.T) {
+	p := NewPool(func() interface{} { return new(int) })
+	sem := p.Semaphore()
+	if err := sem.Acquire(context.Background()); err != nil || !sem.TryAcquire() {
+		t.Errorf("Expected every acquire to succeed, got %v", err)
+	}
+	if n := sem.Available(); n != -1 {
+		t.Errorf("Expected -1 without a limit, got %d", n)
+	}
+	p.Drain(context.Background())
+	if err := sem.Acquire(context.Background()); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+}
+
+// TestSemaphoreViewDrain tests that Drain wakes a blocked Acquire.
+func TestSemaphoreViewDrain(t *testing.T) {
+	p := NewPool(func() interface{} { return new(int) }, WithMaxInUse(1, true))
+	sem := p.Semaphore()
+	obj := p.Get()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- sem.Acquire(context.Background())
+	}()
+	time.Sleep(10 * time.Millisecond)
+	drained := make(chan error, 1)
+	go func() {
+		drained <- p.Drain(context.Background())
+	}()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected ErrPoolClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Drain to wake the blocked Acquire")
+	}
+	p.Put(obj)
+	if err := <-drained; err != nil {
+		t.Errorf("Expected Drain to finish, got %v", err)
+	}
+}
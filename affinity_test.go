@@ -0,0 +1,39 @@
+package pool
+
+import "testing"
+
+// TestAffinity tests that objects go back to the shard they were taken from.
+func TestAffinity(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithAffinity()))
+	p.Get()
+	home := (busiestShard(p.Stats()) + 5) & p.shardMask
+	obj := new(int)
+	p.shards[home].push(entry{obj: obj, meta: &objectMeta{}}, shardCap, nil)
+
+	if got := p.Get(); got != obj {
+		t.Fatal("Expected the object to be stolen from its shard")
+	}
+	p.Put(obj)
+	if st := p.Stats(); st.Shards[home].Idle != 1 {
+		t.Errorf("Expected the object back on shard %d, got %+v", home, st.Shards)
+	}
+}
+
+// TestNoAffinity tests that objects go to the caller's shard by default.
+func TestNoAffinity(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithObjectMetadata()))
+	p.Get()
+	home := (busiestShard(p.Stats()) + 5) & p.shardMask
+	obj := new(int)
+	p.shards[home].push(entry{obj: obj, meta: &objectMeta{}}, shardCap, nil)
+
+	p.Get()
+	p.Put(obj)
+	if st := p.Stats(); st.Shards[home].Idle != 0 {
+		t.Errorf("Expected the object on the caller's shard, got it back on shard %d", home)
+	}
+}
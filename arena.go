@@ -0,0 +1,107 @@
+package pool
+
+import (
+	"context"
+	"sync"
+)
+
+// Default number of objects per slab of ArenaPool
+const defaultArenaSlabSize = 1024
+
+// ArenaPool is a pool of *T whose objects are allocated from slabs, large
+// arrays of T, instead of one by one.
+//
+// Tens of thousands of small objects then cost the GC a handful of
+// allocations to track, and a slab of a pointer-free T is not scanned at all.
+// Objects are zeroed on Put. Clear and Drain release the slabs along with the
+// idle objects, so new objects come from fresh slabs; a released slab is
+// freed by the GC once none of its objects is referenced any more, so objects
+// still checked out stay valid.
+type ArenaPool[T any] struct {
+	arena *arena[T]
+	pool  *Pool
+}
+
+// arena hands out pointers into slabs of T.
+type arena[T any] struct {
+	mu       sync.Mutex
+	slabSize int
+	slab     []T
+	slabs    int
+}
+
+// NewArenaPool creates a new arena pool allocating slabSize objects at a time.
+// If slabSize <= 0 a default of 1024 is used.
+func NewArenaPool[T any](slabSize int, opts ...Option) *ArenaPool[T] {
+	if slabSize <= 0 {
+		slabSize = defaultArenaSlabSize
+	}
+	a := &arena[T]{slabSize: slabSize}
+	return &ArenaPool[T]{
+		arena: a,
+		pool: NewPool(func() interface{} {
+			return a.alloc()
+		}, opts...),
+	}
+}
+
+// alloc returns a pointer to the next free object of the current slab,
+// allocating a new slab when it is exhausted.
+func (a *arena[T]) alloc() *T {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.slab) == 0 {
+		a.slab = make([]T, a.slabSize)
+		a.slabs++
+	}
+	obj := &a.slab[0]
+	a.slab = a.slab[1:]
+	return obj
+}
+
+// release drops the current slab and returns the number of slabs allocated
+// since the previous release.
+func (a *arena[T]) release() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	n := a.slabs
+	a.slab = nil
+	a.slabs = 0
+	return n
+}
+
+// Get retrieves a zeroed object from the pool.
+func (ap *ArenaPool[T]) Get() *T {
+	obj, _ := ap.pool.Get().(*T)
+	return obj
+}
+
+// Put zeroes an object and returns it to the pool.
+func (ap *ArenaPool[T]) Put(obj *T) {
+	if obj == nil {
+		return
+	}
+	var zero T
+	*obj = zero
+	ap.pool.Put(obj)
+}
+
+// Slabs returns the number of slabs allocated since the last Clear or Drain.
+func (ap *ArenaPool[T]) Slabs() int {
+	ap.arena.mu.Lock()
+	defer ap.arena.mu.Unlock()
+	return ap.arena.slabs
+}
+
+// Clear clears all objects from the pool and releases the slabs.
+func (ap *ArenaPool[T]) Clear() {
+	ap.pool.Clear()
+	ap.arena.release()
+}
+
+// Drain shuts the pool down like Pool.Drain and releases the slabs.
+func (ap *ArenaPool[T]) Drain(ctx context.Context) error {
+	err := ap.pool.Drain(ctx)
+	ap.arena.release()
+	return err
+}
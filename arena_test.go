@@ -0,0 +1,52 @@
+package pool
+
+import (
+	"context"
+	"testing"
+)
+
+// TestArenaPool tests that objects come from shared slabs and are zeroed on Put.
+func TestArenaPool(t *testing.T) {
+	ap := NewArenaPool[[2]int](4)
+	predictable(ap.pool)
+
+	objs := make([]*[2]int, 5)
+	for i := range objs {
+		objs[i] = ap.Get()
+	}
+	if n := ap.Slabs(); n != 2 {
+		t.Errorf("Expected 5 objects to take 2 slabs, got %d", n)
+	}
+	if &objs[0][0] == &objs[1][0] {
+		t.Error("Expected distinct objects")
+	}
+
+	objs[0][0] = 1
+	ap.Put(objs[0])
+	if obj := ap.Get(); obj != objs[0] || obj[0] != 0 {
+		t.Errorf("Expected the zeroed object to be reused, got %v", obj)
+	}
+}
+
+// TestArenaPoolClear tests that Clear and Drain release the slabs.
+func TestArenaPoolClear(t *testing.T) {
+	ap := NewArenaPool[int](0)
+	predictable(ap.pool)
+	ap.Put(ap.Get())
+
+	ap.Clear()
+	if n := ap.Slabs(); n != 0 {
+		t.Errorf("Expected Clear to release the slabs, got %d", n)
+	}
+	if n := idleCount(ap.pool); n != 0 {
+		t.Errorf("Expected no idle objects, got %d", n)
+	}
+
+	ap.Put(ap.Get())
+	if err := ap.Drain(context.Background()); err != nil {
+		t.Errorf("Expected Drain to succeed, got %v", err)
+	}
+	if n := ap.Slabs(); n != 0 {
+		t.Errorf("Expected Drain to release the slabs, got %d", n)
+	}
+}
@@ -0,0 +1,95 @@
+package pool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// AutoTuneConfig configures the adaptive shard capacity, see WithAutoTune.
+type AutoTuneConfig struct {
+	// MinCap and MaxCap bound the capacity of each shard
+	MinCap, MaxCap int
+	// Interval between adjustments, one second if zero
+	Interval time.Duration
+	// PerShard tunes the capacity of every shard from its own drops and idle
+	// objects, so shards taking more Puts under skew get more capacity than the others
+	PerShard bool
+}
+
+// startAutoTune starts adjusting the shard capacity of p every interval.
+func (p *Pool) startAutoTune(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	prev := p.Stats()
+	p.runTask(interval, func(p *Pool) {
+		prev = p.autoTune(prev)
+	})
+}
+
+// autoTune adjusts the shard capacity from the activity since prev and returns
+// the current stats for the next round, see tuneCap. Objects above a lowered
+// capacity are trimmed.
+func (p *Pool) autoTune(prev Stats) Stats {
+	cur, d := p.StatsDelta(prev)
+	cfg := p.config()
+	tune := cfg.autoTune
+	if tune == nil {
+		return cur
+	}
+	if tune.PerShard {
+		return p.autoTuneShards(cur, d, cfg)
+	}
+	capacity := tuneCap(cfg.shardCap, d.Drops, d.Misses, cur.Idle, len(p.shards), tune)
+	if capacity == cfg.shardCap {
+		return cur
+	}
+	p.updateConfig(func(c *config) {
+		c.shardCap = capacity
+	})
+	if capacity < cfg.shardCap {
+		p.Trim(capacity)
+	}
+	return p.Stats()
+}
+
+// autoTuneShards adjusts the capacity of every shard from its own drops and
+// idle objects and the misses of the pool in d, and returns the current stats
+// for the next round. Misses count for the whole pool, since a Get that missed
+// could have stolen an object from any shard that dropped one.
+func (p *Pool) autoTuneShards(cur, d Stats, cfg *config) Stats {
+	changed := false
+	for i := range p.shards {
+		shard := &p.shards[i]
+		sh := d.Shards[i]
+		old := shard.limit(cfg)
+		capacity := tuneCap(old, sh.Drops, d.Misses, cur.Shards[i].Idle, 1, cfg.autoTune)
+		if capacity == old {
+			continue
+		}
+		atomic.StoreInt64(&shard.capacity, int64(capacity))
+		if capacity < old {
+			p.trimShard(i, capacity, cfg)
+		}
+		changed = true
+	}
+	if !changed {
+		return cur
+	}
+	return p.Stats()
+}
+
+// tuneCap returns the capacity following capacity for shards that dropped
+// drops Puts, missed misses Gets and hold idle objects.
+// The capacity doubles when Puts were dropped while Gets missed, since a larger
+// pool would have served them. It shrinks by a quarter when nothing missed and
+// the shards stayed more than half full. It stays within the bounds of tune.
+func tuneCap(capacity int, drops, misses uint64, idle, shards int, tune *AutoTuneConfig) int {
+	switch {
+	case drops > 0 && misses > 0:
+		capacity *= 2
+	case misses == 0 && idle*2 > capacity*shards:
+		capacity -= max(capacity/4, 1)
+	}
+	return min(max(capacity, tune.MinCap), tune.MaxCap)
+}
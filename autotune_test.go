@@ -0,0 +1,94 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAutoTuneGrow tests that the capacity grows when Puts are dropped while Gets miss.
+func TestAutoTuneGrow(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithAutoTune(AutoTuneConfig{MinCap: 1, MaxCap: 4, Interval: time.Hour})))
+	p.updateConfig(func(c *config) {
+		c.shardCap = 1
+	})
+	prev := p.Stats()
+
+	a, b := p.Get(), p.Get()
+	p.Put(a)
+	p.Put(b)
+	prev = p.autoTune(prev)
+	if c := p.View().Config.ShardCap; c != 2 {
+		t.Fatalf("Expected capacity 2, got %d", c)
+	}
+
+	a, b, c := p.Get(), p.Get(), p.Get()
+	p.Put(a)
+	p.Put(b)
+	p.Put(c)
+	p.autoTune(prev)
+	if c := p.View().Config.ShardCap; c != 4 {
+		t.Errorf("Expected capacity to stop at 4, got %d", c)
+	}
+}
+
+// TestAutoTuneShrink tests that the capacity shrinks while the shards stay full without misses.
+func TestAutoTuneShrink(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithAutoTune(AutoTuneConfig{MinCap: 2, MaxCap: 8, Interval: time.Hour})))
+	if c := p.View().Config.ShardCap; c != 8 {
+		t.Fatalf("Expected the initial capacity to be clamped to 8, got %d", c)
+	}
+	for i := range p.shards {
+		for j := 0; j < 8; j++ {
+			p.shards[i].push(entry{obj: new(int)}, 8, nil)
+		}
+	}
+
+	prev := p.autoTune(p.Stats())
+	if c := p.View().Config.ShardCap; c != 6 {
+		t.Fatalf("Expected capacity 6, got %d", c)
+	}
+	if n := idleCount(p); n != 6*len(p.shards) {
+		t.Errorf("Expected shards to be trimmed to 6, got %d idle", n)
+	}
+	for i := 0; i < 10; i++ {
+		prev = p.autoTune(prev)
+	}
+	if c := p.View().Config.ShardCap; c != 2 {
+		t.Errorf("Expected capacity to stop at 2, got %d", c)
+	}
+}
+
+// TestAutoTunePerShard tests that only the shard dropping Puts grows when Gets miss.
+func TestAutoTunePerShard(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithAutoTune(AutoTuneConfig{MinCap: 1, MaxCap: 4, Interval: time.Hour, PerShard: true})))
+	p.updateConfig(func(c *config) {
+		c.shardCap = 1
+	})
+	prev := p.Stats()
+
+	a, b := p.Get(), p.Get()
+	p.Put(a)
+	p.Put(b)
+	st := p.autoTune(prev)
+	if st.Drops != 1 {
+		t.Fatalf("Expected 1 dropped Put, got %d", st.Drops)
+	}
+	for i, sh := range st.Shards {
+		want := 1
+		if sh.Drops > 0 {
+			want = 2
+		}
+		if sh.Capacity != want {
+			t.Errorf("Expected shard %d to have capacity %d, got %d", i, want, sh.Capacity)
+		}
+	}
+	if c := p.View().Config.ShardCap; c != 1 {
+		t.Errorf("Expected the configured capacity to stay 1, got %d", c)
+	}
+}
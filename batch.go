@@ -0,0 +1,64 @@
+package pool
+
+import (
+	"context"
+	"sync"
+)
+
+// RunBatches processes the items received from in with up to parallelism
+// goroutines until in is closed. Every item is passed to fn with an object
+// taken from p, which is returned to p once fn is done, even if fn fails or
+// panics, so a pipeline can never leak pooled objects.
+//
+// The first error returned by fn or by taking an object cancels the others
+// and is returned; items still in in are then left unread. If ctx is done
+// first, ctx.Err() is returned. parallelism <= 0 means one goroutine.
+func RunBatches[T any](ctx context.Context, p *Pool, in <-chan T, parallelism int, fn func(obj interface{}, item T) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for i := 0; i < max(parallelism, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					fail(ctx.Err())
+					return
+				case item, ok := <-in:
+					if !ok {
+						return
+					}
+					if err := runItem(ctx, p, item, fn); err != nil {
+						fail(err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// runItem passes item to fn with an object of p and returns the object.
+func runItem[T any](ctx context.Context, p *Pool, item T, fn func(obj interface{}, item T) error) error {
+	obj, err := p.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer p.Put(obj)
+	return fn(obj, item)
+}
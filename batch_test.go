@@ -0,0 +1,60 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+// TestRunBatches tests that every item is processed and every object returned.
+func TestRunBatches(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+	in := make(chan int)
+	go func() {
+		for i := 1; i <= 100; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	var sum int64
+	err := RunBatches(context.Background(), p, in, 4, func(obj interface{}, item int) error {
+		*obj.(*int) = item
+		atomic.AddInt64(&sum, int64(item))
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if sum != 5050 {
+		t.Errorf("Expected every item to be processed, got sum %d", sum)
+	}
+	if n := p.checkedOut(); n != 0 {
+		t.Errorf("Expected every object to be returned, got %d checked out", n)
+	}
+}
+
+// TestRunBatchesError tests that the first error stops the pipeline.
+func TestRunBatchesError(t *testing.T) {
+	errTest := errors.New("test")
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+	in := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		in <- i
+	}
+
+	err := RunBatches(context.Background(), p, in, 2, func(obj interface{}, item int) error {
+		return errTest
+	})
+	if err != errTest {
+		t.Errorf("Expected the fn error, got %v", err)
+	}
+	if n := p.checkedOut(); n != 0 {
+		t.Errorf("Expected every object to be returned, got %d checked out", n)
+	}
+}
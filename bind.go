@@ -0,0 +1,88 @@
+package pool
+
+import "io"
+
+// Proxy is an io.Reader and io.Writer forwarding to the targets a BindPool
+// binds it to. Objects that cannot be reset to a new source or destination,
+// such as json.Decoder and json.Encoder, are built once around a Proxy and
+// re-bound by switching its targets.
+type Proxy struct {
+	// R is the reader Read forwards to
+	R io.Reader
+	// W is the writer Write forwards to
+	W io.Writer
+}
+
+// Read reads from the bound reader.
+func (p *Proxy) Read(b []byte) (int, error) {
+	return p.R.Read(b)
+}
+
+// Write writes to the bound writer.
+func (p *Proxy) Write(b []byte) (int, error) {
+	return p.W.Write(b)
+}
+
+// Bound is an object of a BindPool bound to a reader and writer.
+type Bound[T any] struct {
+	// Value is the pooled object
+	Value T
+	proxy *Proxy
+	err   error
+}
+
+// Fail records that the object failed, e.g. with the error of a write, so Put
+// discards it instead of retaining an object in an unknown state.
+func (b *Bound[T]) Fail(err error) {
+	if err != nil && b.err == nil {
+		b.err = err
+	}
+}
+
+// BindPool is a pool of objects built around a Proxy: Get binds the proxy to
+// its reader and writer, and Put unbinds it, so the pooled objects do not keep
+// their last source or destination alive.
+type BindPool[T any] struct {
+	pool     *Pool
+	reusable func(obj T) bool
+}
+
+// NewBindPool creates a new bind pool building its objects with build.
+// reusable, if not nil, is called on Put and reports whether an object that
+// did not fail can be reused, e.g. whether a decoder consumed all of its
+// buffered input.
+func NewBindPool[T any](build func(p *Proxy) T, reusable func(obj T) bool, opts ...Option) *BindPool[T] {
+	return &BindPool[T]{
+		reusable: reusable,
+		pool: NewPool(func() interface{} {
+			p := &Proxy{}
+			return &Bound[T]{Value: build(p), proxy: p}
+		}, opts...),
+	}
+}
+
+// Get retrieves an object bound to r and w; either may be nil if the object
+// does not read or write.
+func (bp *BindPool[T]) Get(r io.Reader, w io.Writer) (*Bound[T], error) {
+	obj, err := bp.pool.GetE()
+	if err != nil {
+		return nil, err
+	}
+	b := obj.(*Bound[T])
+	b.proxy.R, b.proxy.W = r, w
+	return b, nil
+}
+
+// Put unbinds an object and returns it to the pool, or discards it if it
+// failed or is not reusable.
+func (bp *BindPool[T]) Put(b *Bound[T]) {
+	if b == nil {
+		return
+	}
+	b.proxy.R, b.proxy.W = nil, nil
+	if b.err != nil || (bp.reusable != nil && !bp.reusable(b.Value)) {
+		bp.pool.Discard(b)
+		return
+	}
+	bp.pool.Put(b)
+}
@@ -0,0 +1,41 @@
+package pool
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestBindPool tests that objects are re-bound on Get and discarded after failing.
+func TestBindPool(t *testing.T) {
+	bp := NewBindPool(func(p *Proxy) *bufio.Writer {
+		return bufio.NewWriterSize(p, 16)
+	}, nil)
+	predictable(bp.pool)
+
+	var first, second bytes.Buffer
+	b, _ := bp.Get(nil, &first)
+	b.Value.WriteString("one")
+	b.Value.Flush()
+	bp.Put(b)
+	if b.proxy.W != nil {
+		t.Error("Expected Put to unbind the writer")
+	}
+
+	b2, _ := bp.Get(nil, &second)
+	if b2 != b {
+		t.Error("Expected the object to be reused")
+	}
+	b2.Value.WriteString("two")
+	b2.Value.Flush()
+	if first.String() != "one" || second.String() != "two" {
+		t.Errorf("Expected each target to get its own output, got %q and %q", first.String(), second.String())
+	}
+
+	b2.Fail(errors.New("broken"))
+	bp.Put(b2)
+	if b3, _ := bp.Get(nil, &second); b3 == b2 {
+		t.Error("Expected the failed object to be discarded")
+	}
+}
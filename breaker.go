@@ -0,0 +1,67 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// circuitBreaker stops calling a failing factory for a cool-down period.
+// After the configured number of consecutive failures it opens; once the
+// cool-down has passed a single probe call is let through, which closes the
+// breaker on success and reopens it on failure.
+// The zero value is a closed breaker.
+type circuitBreaker struct {
+	failures  int64
+	openUntil int64
+	probing   int32
+}
+
+// allow reports whether the factory may be called at now.
+func (b *circuitBreaker) allow(now int64) bool {
+	until := atomic.LoadInt64(&b.openUntil)
+	if until == 0 {
+		return true
+	}
+	if now < until {
+		return false
+	}
+	return atomic.CompareAndSwapInt32(&b.probing, 0, 1)
+}
+
+// success records a successful factory call and closes the breaker.
+func (b *circuitBreaker) success() {
+	if atomic.LoadInt64(&b.failures) != 0 {
+		atomic.StoreInt64(&b.failures, 0)
+	}
+	if atomic.LoadInt64(&b.openUntil) != 0 {
+		atomic.StoreInt64(&b.openUntil, 0)
+		atomic.StoreInt32(&b.probing, 0)
+	}
+}
+
+// failure records a failed factory call and opens the breaker once the
+// failure threshold of cfg is reached.
+func (b *circuitBreaker) failure(cfg *config, now int64) {
+	if atomic.AddInt64(&b.failures, 1) >= int64(cfg.breakerFailures) {
+		atomic.StoreInt64(&b.openUntil, now+int64(cfg.breakerCoolDown))
+		atomic.StoreInt32(&b.probing, 0)
+	}
+}
+
+// newObjectBreaker creates a new object through the circuit breaker.
+// Failures caused by the cancellation of ctx do not count against the factory.
+func (p *Pool) newObjectBreaker(ctx context.Context, cfg *config, factory func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if !p.breaker.allow(cfg.clock.Now().UnixNano()) {
+		return nil, ErrCircuitOpen
+	}
+	obj, err := p.callFactory(ctx, cfg, factory)
+	switch {
+	case err == nil:
+		p.breaker.success()
+	case ctx.Err() != nil:
+		atomic.CompareAndSwapInt32(&p.breaker.probing, 1, 0)
+	default:
+		p.breaker.failure(cfg, cfg.clock.Now().UnixNano())
+	}
+	return obj, err
+}
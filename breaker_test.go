@@ -0,0 +1,58 @@
+package pool
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCircuitBreaker tests that the breaker opens after failures and closes after a successful probe.
+func TestCircuitBreaker(t *testing.T) {
+	errTest := errors.New("test")
+	calls := 0
+	fail := true
+	p := NewPoolE(func() (interface{}, error) {
+		calls++
+		if fail {
+			return nil, errTest
+		}
+		return new(int), nil
+	}, WithCircuitBreaker(2, 20*time.Millisecond))
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.GetE(); err != errTest {
+			t.Errorf("Expected factory error, got %v", err)
+		}
+	}
+	if _, err := p.GetE(); err != ErrCircuitOpen {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the open breaker to skip the factory, got %d calls", calls)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	fail = false
+	if _, err := p.GetE(); err != nil {
+		t.Errorf("Expected the probe to succeed, got %v", err)
+	}
+	if _, err := p.GetE(); err != nil {
+		t.Errorf("Expected the breaker to be closed, got %v", err)
+	}
+}
+
+// TestCircuitBreakerReopen tests that a failed probe reopens the breaker.
+func TestCircuitBreakerReopen(t *testing.T) {
+	p := NewPoolE(func() (interface{}, error) {
+		return nil, errors.New("test")
+	}, WithCircuitBreaker(1, 20*time.Millisecond))
+
+	p.GetE()
+	time.Sleep(30 * time.Millisecond)
+	if _, err := p.GetE(); err == ErrCircuitOpen {
+		t.Error("Expected a probe after the cool-down")
+	}
+	if _, err := p.GetE(); err != ErrCircuitOpen {
+		t.Errorf("Expected the failed probe to reopen the breaker, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package pool
+
+import "bytes"
+
+const (
+	// Size of the smallest buffer class (1KB)
+	minBufferClassShift = 10
+	// Size of the largest buffer class (1MB)
+	maxBufferClassShift = 20
+	// Count of buffer classes
+	bufferClassCount = maxBufferClassShift - minBufferClassShift + 1
+)
+
+// BufferPool is a pool of *bytes.Buffer split into power-of-two size classes.
+// Each class is backed by its own Pool, so a request for a small buffer never
+// hands out (and pins) a large one.
+type BufferPool struct {
+	classes [bufferClassCount]*Pool
+}
+
+// NewBufferPool creates a new buffer pool with size classes from 1KB to 1MB.
+func NewBufferPool() *BufferPool {
+	bp := &BufferPool{}
+	for i := range bp.classes {
+		size := 1 << (minBufferClassShift + i)
+		bp.classes[i] = NewPool(func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, size))
+		})
+	}
+	return bp
+}
+
+// GetBuffer retrieves an empty buffer with a capacity of at least minSize.
+// Requests larger than the biggest class are allocated directly.
+func (bp *BufferPool) GetBuffer(minSize int) *bytes.Buffer {
+	idx := bufferGetClass(minSize)
+	if idx >= bufferClassCount {
+		return bytes.NewBuffer(make([]byte, 0, minSize))
+	}
+	buf := bp.classes[idx].Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// Put returns a buffer to the class matching its capacity.
+// Buffers smaller than the smallest class or larger than the biggest class are dropped.
+func (bp *BufferPool) Put(buf *bytes.Buffer) {
+	if buf == nil {
+		return
+	}
+	idx := bufferPutClass(buf.Cap())
+	if idx < 0 || idx >= bufferClassCount {
+		return
+	}
+	buf.Reset()
+	bp.classes[idx].Put(buf)
+}
+
+// Clear clears all buffers from the pool.
+func (bp *BufferPool) Clear() {
+	for _, p := range bp.classes {
+		p.Clear()
+	}
+}
+
+// bufferGetClass returns the smallest class whose buffers can hold size bytes.
+func bufferGetClass(size int) int {
+	return getClass(size, minBufferClassShift)
+}
+
+// bufferPutClass returns the largest class whose size does not exceed capacity,
+// or -1 if capacity is below the smallest class.
+func bufferPutClass(capacity int) int {
+	return putClass(capacity, minBufferClassShift)
+}
@@ -0,0 +1,69 @@
+package pool
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestBufferPoolGet tests that GetBuffer returns empty buffers of sufficient capacity.
+func TestBufferPoolGet(t *testing.T) {
+	bp := NewBufferPool()
+
+	for _, size := range []int{0, 1, 1024, 1025, 4000, 1 << 20, 1<<20 + 1} {
+		buf := bp.GetBuffer(size)
+		if buf.Len() != 0 {
+			t.Errorf("Expected empty buffer for size %d, got len %d", size, buf.Len())
+		}
+		if buf.Cap() < size {
+			t.Errorf("Expected capacity >= %d, got %d", size, buf.Cap())
+		}
+		bp.Put(buf)
+	}
+}
+
+// TestBufferPoolRouting tests that Put routes buffers to the matching class.
+func TestBufferPoolRouting(t *testing.T) {
+	bp := NewBufferPool()
+	for _, p := range bp.classes {
+		predictable(p)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 5000))
+	buf.WriteString("dirty")
+	bp.Put(buf)
+
+	// 5000 bytes falls into the 4KB class
+	for i, p := range bp.classes {
+		want := 0
+		if i == 2 {
+			want = 1
+		}
+		if n := idleCount(p); n != want {
+			t.Errorf("Expected %d buffers in class %d, got %d", want, i, n)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Error("Expected returned buffer to be reset")
+	}
+}
+
+// TestBufferClass tests the size class calculation.
+func TestBufferClass(t *testing.T) {
+	cases := []struct {
+		size, get, put int
+	}{
+		{512, 0, -1},
+		{1024, 0, 0},
+		{1025, 1, 0},
+		{2048, 1, 1},
+		{1 << 20, bufferClassCount - 1, bufferClassCount - 1},
+	}
+	for _, c := range cases {
+		if got := bufferGetClass(c.size); got != c.get {
+			t.Errorf("bufferGetClass(%d) = %d, expected %d", c.size, got, c.get)
+		}
+		if got := bufferPutClass(c.size); got != c.put {
+			t.Errorf("bufferPutClass(%d) = %d, expected %d", c.size, got, c.put)
+		}
+	}
+}
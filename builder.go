@@ -0,0 +1,60 @@
+package pool
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Default max capacity of builders retained by StringBuilderPool (64KB)
+const defaultBuilderMaxCap = 64 << 10
+
+// StringBuilderPool is a pool of *strings.Builder.
+//
+// Strings returned by Builder.String share the builder's buffer, so the buffer
+// itself can never be safely reused. Put therefore always calls Reset, which
+// releases the buffer, and the pool instead remembers the capacity of recently
+// returned builders and pre-grows builders on Get. Builders that grew beyond
+// the max capacity are dropped and do not influence the size hint.
+type StringBuilderPool struct {
+	maxCap   int
+	sizeHint int64
+	pool     *Pool
+}
+
+// NewStringBuilderPool creates a new builder pool.
+// maxCap bounds the capacity that builders are pre-grown to; if maxCap <= 0 a default of 64KB is used.
+func NewStringBuilderPool(maxCap int) *StringBuilderPool {
+	if maxCap <= 0 {
+		maxCap = defaultBuilderMaxCap
+	}
+	return &StringBuilderPool{
+		maxCap: maxCap,
+		pool: NewPool(func() interface{} {
+			return new(strings.Builder)
+		}),
+	}
+}
+
+// Get retrieves an empty builder from the pool.
+func (bp *StringBuilderPool) Get() *strings.Builder {
+	b := bp.pool.Get().(*strings.Builder)
+	if hint := int(atomic.LoadInt64(&bp.sizeHint)); hint > 0 {
+		b.Grow(hint)
+	}
+	return b
+}
+
+// Put resets a builder and returns it to the pool.
+// Builders whose capacity exceeds the max capacity are dropped.
+func (bp *StringBuilderPool) Put(b *strings.Builder) {
+	if b == nil {
+		return
+	}
+	if c := b.Cap(); c > bp.maxCap {
+		return
+	} else if c > 0 {
+		atomic.StoreInt64(&bp.sizeHint, int64(c))
+	}
+	b.Reset()
+	bp.pool.Put(b)
+}
@@ -0,0 +1,45 @@
+package pool
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestStringBuilderPool tests that returned builders are reset and strings stay intact.
+func TestStringBuilderPool(t *testing.T) {
+	bp := NewStringBuilderPool(0)
+
+	b := bp.Get()
+	b.WriteString("hello")
+	s := b.String()
+	bp.Put(b)
+
+	if b.Len() != 0 {
+		t.Error("Expected builder to be reset on Put")
+	}
+
+	b = bp.Get()
+	if b.Cap() < len("hello") {
+		t.Errorf("Expected builder to be pre-grown, got cap %d", b.Cap())
+	}
+	b.WriteString("world")
+	if s != "hello" {
+		t.Errorf("Expected previous string to be intact, got %q", s)
+	}
+}
+
+// TestStringBuilderPoolMaxCap tests that oversized builders are dropped.
+func TestStringBuilderPoolMaxCap(t *testing.T) {
+	bp := NewStringBuilderPool(16)
+
+	b := new(strings.Builder)
+	b.Grow(1024)
+	bp.Put(b)
+
+	if n := idleCount(bp.pool); n != 0 {
+		t.Errorf("Expected oversized builder to be dropped, got %d idle", n)
+	}
+	if bp.Get().Cap() != 0 {
+		t.Error("Expected oversized builder not to affect the size hint")
+	}
+}
@@ -0,0 +1,249 @@
+package pool
+
+import (
+	"container/list"
+	"errors"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+)
+
+// errCacheLoadPanicked is returned to the callers waiting for a load that panicked.
+var errCacheLoadPanicked = errors.New("pool: cache load panicked")
+
+// CachePool is a bounded, sharded cache of values by key. Unlike Pool, which
+// hands an object to one caller at a time, it returns the same value for the
+// same key to every caller until the value is evicted; it is the sibling to
+// use instead of abusing a Pool as a cache.
+//
+// Keys are spread over as many shards as a Pool has, each holding its share
+// of the capacity and evicting its least recently used value when full.
+// Concurrent misses for the same key are collapsed into one load.
+type CachePool[K comparable, V any] struct {
+	shards    [shardCount]cacheShard[K, V]
+	seed      maphash.Seed
+	load      func(key K) (V, error)
+	onEvict   func(key K, value V)
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+// cacheShard is a shard of a CachePool.
+type cacheShard[K comparable, V any] struct {
+	mu      sync.Mutex
+	cap     int
+	items   map[K]*list.Element
+	lru     list.List
+	loading map[K]*cacheLoad[V]
+}
+
+// cacheItem is a cached value, the value of an element of cacheShard.lru.
+type cacheItem[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// cacheLoad is a load in flight for a key.
+type cacheLoad[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// CacheStats holds the counters of a CachePool.
+type CacheStats struct {
+	// Number of Gets served from the cache
+	Hits int64
+	// Number of Gets that loaded the value
+	Misses int64
+	// Number of values evicted to make room for others
+	Evictions int64
+}
+
+// NewCachePool creates a new cache holding up to capacity values, loading the
+// value of a missing key with load. Values that fail to load are not cached.
+// If capacity < shardCount, every shard still holds one value.
+func NewCachePool[K comparable, V any](capacity int, load func(key K) (V, error)) *CachePool[K, V] {
+	if load == nil {
+		panic("load cannot be nil")
+	}
+	cp := &CachePool[K, V]{seed: maphash.MakeSeed(), load: load}
+	perShard := max((capacity+shardCount-1)/shardCount, 1)
+	for i := range cp.shards {
+		cp.shards[i].cap = perShard
+		cp.shards[i].items = make(map[K]*list.Element)
+		cp.shards[i].loading = make(map[K]*cacheLoad[V])
+	}
+	return cp
+}
+
+// OnEvict sets a function called with the values evicted to make room for
+// others, replaced by Set or removed by Delete and Clear, e.g. to close them.
+// It must be set before the cache is used.
+func (cp *CachePool[K, V]) OnEvict(fn func(key K, value V)) {
+	cp.onEvict = fn
+}
+
+// shard returns the shard of key.
+func (cp *CachePool[K, V]) shard(key K) *cacheShard[K, V] {
+	return &cp.shards[maphash.Comparable(cp.seed, key)&(shardCount-1)]
+}
+
+// Get returns the value of key, loading it on a miss. Callers missing the
+// same key while it loads wait for that load and share its result.
+func (cp *CachePool[K, V]) Get(key K) (V, error) {
+	s := cp.shard(key)
+	s.mu.Lock()
+	if e, ok := s.items[key]; ok {
+		s.lru.MoveToFront(e)
+		v := e.Value.(*cacheItem[K, V]).value
+		s.mu.Unlock()
+		cp.hits.Add(1)
+		return v, nil
+	}
+	if l, ok := s.loading[key]; ok {
+		s.mu.Unlock()
+		<-l.done
+		return l.value, l.err
+	}
+	l := &cacheLoad[V]{done: make(chan struct{})}
+	s.loading[key] = l
+	s.mu.Unlock()
+	cp.misses.Add(1)
+
+	loaded := false
+	defer func() {
+		if !loaded {
+			// load panicked, fail the waiters and cache nothing
+			l.err = errCacheLoadPanicked
+		}
+		s.mu.Lock()
+		delete(s.loading, key)
+		var replaced *cacheItem[K, V]
+		var evicted []*cacheItem[K, V]
+		if l.err == nil {
+			replaced, evicted = s.setLocked(key, l.value)
+		}
+		s.mu.Unlock()
+		close(l.done)
+		cp.removed(replaced, evicted)
+	}()
+	l.value, l.err = cp.load(key)
+	loaded = true
+	return l.value, l.err
+}
+
+// Peek returns the value of key without loading it or marking it as used.
+func (cp *CachePool[K, V]) Peek(key K) (V, bool) {
+	s := cp.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.items[key]; ok {
+		return e.Value.(*cacheItem[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set caches value for key, replacing its current value.
+func (cp *CachePool[K, V]) Set(key K, value V) {
+	s := cp.shard(key)
+	s.mu.Lock()
+	replaced, evicted := s.setLocked(key, value)
+	s.mu.Unlock()
+	cp.removed(replaced, evicted)
+}
+
+// setLocked caches value for key and returns the item it replaced, if any,
+// and the items evicted to make room.
+// s.mu must be held.
+func (s *cacheShard[K, V]) setLocked(key K, value V) (*cacheItem[K, V], []*cacheItem[K, V]) {
+	if e, ok := s.items[key]; ok {
+		old := e.Value.(*cacheItem[K, V])
+		e.Value = &cacheItem[K, V]{key: key, value: value}
+		s.lru.MoveToFront(e)
+		return old, nil
+	}
+	var evicted []*cacheItem[K, V]
+	for s.lru.Len() >= s.cap {
+		item := s.lru.Remove(s.lru.Back()).(*cacheItem[K, V])
+		delete(s.items, item.key)
+		evicted = append(evicted, item)
+	}
+	s.items[key] = s.lru.PushFront(&cacheItem[K, V]{key: key, value: value})
+	return nil, evicted
+}
+
+// Delete removes key from the cache and reports whether it was cached.
+func (cp *CachePool[K, V]) Delete(key K) bool {
+	s := cp.shard(key)
+	s.mu.Lock()
+	e, ok := s.items[key]
+	if ok {
+		s.lru.Remove(e)
+		delete(s.items, key)
+	}
+	s.mu.Unlock()
+	if ok && cp.onEvict != nil {
+		item := e.Value.(*cacheItem[K, V])
+		cp.onEvict(item.key, item.value)
+	}
+	return ok
+}
+
+// Len returns the number of cached values.
+func (cp *CachePool[K, V]) Len() int {
+	n := 0
+	for i := range cp.shards {
+		s := &cp.shards[i]
+		s.mu.Lock()
+		n += s.lru.Len()
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// Clear removes all values from the cache.
+func (cp *CachePool[K, V]) Clear() {
+	for i := range cp.shards {
+		s := &cp.shards[i]
+		s.mu.Lock()
+		var removed []*cacheItem[K, V]
+		if cp.onEvict != nil {
+			for e := s.lru.Front(); e != nil; e = e.Next() {
+				removed = append(removed, e.Value.(*cacheItem[K, V]))
+			}
+		}
+		s.lru.Init()
+		clear(s.items)
+		s.mu.Unlock()
+		for _, item := range removed {
+			cp.onEvict(item.key, item.value)
+		}
+	}
+}
+
+// Stats returns the counters of the cache.
+func (cp *CachePool[K, V]) Stats() CacheStats {
+	return CacheStats{
+		Hits:      cp.hits.Load(),
+		Misses:    cp.misses.Load(),
+		Evictions: cp.evictions.Load(),
+	}
+}
+
+// removed passes a replaced item and the evicted ones to onEvict, counting
+// the evictions.
+func (cp *CachePool[K, V]) removed(replaced *cacheItem[K, V], evicted []*cacheItem[K, V]) {
+	cp.evictions.Add(int64(len(evicted)))
+	if cp.onEvict == nil {
+		return
+	}
+	if replaced != nil {
+		cp.onEvict(replaced.key, replaced.value)
+	}
+	for _, item := range evicted {
+		cp.onEvict(item.key, item.value)
+	}
+}
@@ -0,0 +1,105 @@
+package pool
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCachePool tests that the same value is returned for the same key.
+func TestCachePool(t *testing.T) {
+	var loads int32
+	cp := NewCachePool(64, func(key int) (*int, error) {
+		atomic.AddInt32(&loads, 1)
+		v := key * 2
+		return &v, nil
+	})
+
+	a, err := cp.Get(1)
+	if err != nil || *a != 2 {
+		t.Fatalf("Expected 2, got %v and %v", a, err)
+	}
+	if b, _ := cp.Get(1); b != a {
+		t.Error("Expected the cached value to be returned")
+	}
+	if loads != 1 {
+		t.Errorf("Expected 1 load, got %d", loads)
+	}
+	if s := cp.Stats(); s.Hits != 1 || s.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %+v", s)
+	}
+
+	if !cp.Delete(1) || cp.Delete(1) {
+		t.Error("Expected Delete to report whether the key was cached")
+	}
+	if _, ok := cp.Peek(1); ok {
+		t.Error("Expected the deleted key to be missing")
+	}
+}
+
+// TestCachePoolEviction tests that full shards evict their least recently used value.
+func TestCachePoolEviction(t *testing.T) {
+	var evicted []int
+	cp := NewCachePool(shardCount*2, func(key int) (int, error) { return key, nil })
+	cp.OnEvict(func(key, value int) { evicted = append(evicted, key) })
+
+	for i := 0; i < 1000; i++ {
+		cp.Get(i)
+	}
+	if n := cp.Len(); n != shardCount*2 {
+		t.Errorf("Expected the cache to be bounded to %d values, got %d", shardCount*2, n)
+	}
+	if s := cp.Stats(); s.Evictions != int64(len(evicted)) || len(evicted) != 1000-shardCount*2 {
+		t.Errorf("Expected %d evictions, got %d and %d", 1000-shardCount*2, s.Evictions, len(evicted))
+	}
+	if _, ok := cp.Peek(999); !ok {
+		t.Error("Expected the most recent key to be cached")
+	}
+
+	cp.Set(999, -1)
+	if v, _ := cp.Peek(999); v != -1 {
+		t.Errorf("Expected Set to replace the value, got %d", v)
+	}
+	cp.Clear()
+	if cp.Len() != 0 {
+		t.Error("Expected Clear to empty the cache")
+	}
+}
+
+// TestCachePoolLoad tests that concurrent misses share one load and errors are not cached.
+func TestCachePoolLoad(t *testing.T) {
+	var loads int32
+	fail := errors.New("fail")
+	cp := NewCachePool(16, func(key string) (string, error) {
+		atomic.AddInt32(&loads, 1)
+		time.Sleep(10 * time.Millisecond)
+		if key == "bad" {
+			return "", fail
+		}
+		return key, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := cp.Get("k"); err != nil || v != "k" {
+				t.Errorf("Expected k, got %q and %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if loads != 1 {
+		t.Errorf("Expected concurrent misses to share 1 load, got %d", loads)
+	}
+
+	if _, err := cp.Get("bad"); !errors.Is(err, fail) {
+		t.Errorf("Expected the load error, got %v", err)
+	}
+	if _, ok := cp.Peek("bad"); ok {
+		t.Error("Expected the failed value not to be cached")
+	}
+}
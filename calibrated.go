@@ -0,0 +1,126 @@
+package pool
+
+import (
+	"bytes"
+	"sort"
+	"sync/atomic"
+)
+
+const (
+	// Size of the smallest calibration class (64B)
+	minCalibrateClassShift = 6
+	// Count of calibration classes, the largest one is 32MB
+	calibrateClassCount = 20
+	// Number of Puts into a single class that trigger a calibration
+	calibrateCallsThreshold = 42000
+	// Fraction of Puts whose buffers must fit into the calibrated max size
+	calibrateMaxPercentile = 0.95
+)
+
+// CalibratedBufferPool is a pool of *bytes.Buffer that tracks the distribution
+// of returned buffer sizes. It periodically calibrates a default size for new
+// buffers and a max size above which returned buffers are discarded, so a few
+// giant buffers cannot pin memory for the lifetime of the pool.
+type CalibratedBufferPool struct {
+	calls       [calibrateClassCount]uint64
+	calibrating uint64
+	defaultSize uint64
+	maxSize     uint64
+	pool        *Pool
+}
+
+// NewCalibratedBufferPool creates a new calibrated buffer pool.
+func NewCalibratedBufferPool() *CalibratedBufferPool {
+	bp := &CalibratedBufferPool{}
+	bp.pool = NewPool(func() interface{} {
+		return bytes.NewBuffer(make([]byte, 0, atomic.LoadUint64(&bp.defaultSize)))
+	})
+	return bp
+}
+
+// Get retrieves an empty buffer from the pool.
+func (bp *CalibratedBufferPool) Get() *bytes.Buffer {
+	return bp.pool.Get().(*bytes.Buffer)
+}
+
+// Put returns a buffer to the pool.
+// The buffer is discarded if its capacity exceeds the calibrated max size.
+func (bp *CalibratedBufferPool) Put(buf *bytes.Buffer) {
+	if buf == nil {
+		return
+	}
+	idx := calibrateClass(buf.Len())
+	if atomic.AddUint64(&bp.calls[idx], 1) > calibrateCallsThreshold {
+		bp.calibrate()
+	}
+
+	maxSize := int(atomic.LoadUint64(&bp.maxSize))
+	if maxSize == 0 || buf.Cap() <= maxSize {
+		buf.Reset()
+		bp.pool.Put(buf)
+	}
+}
+
+// DefaultSize returns the calibrated capacity of newly created buffers.
+func (bp *CalibratedBufferPool) DefaultSize() int {
+	return int(atomic.LoadUint64(&bp.defaultSize))
+}
+
+// MaxSize returns the calibrated max capacity of retained buffers.
+// Zero means the pool has not been calibrated yet and retains everything.
+func (bp *CalibratedBufferPool) MaxSize() int {
+	return int(atomic.LoadUint64(&bp.maxSize))
+}
+
+// calibrate recomputes the default and max sizes from the recorded calls and
+// resets the counters. Only one calibration runs at a time.
+func (bp *CalibratedBufferPool) calibrate() {
+	if !atomic.CompareAndSwapUint64(&bp.calibrating, 0, 1) {
+		return
+	}
+
+	type classCalls struct {
+		calls uint64
+		size  uint64
+	}
+	stats := make([]classCalls, 0, calibrateClassCount)
+	var total uint64
+	for i := range bp.calls {
+		calls := atomic.SwapUint64(&bp.calls[i], 0)
+		total += calls
+		stats = append(stats, classCalls{
+			calls: calls,
+			size:  1 << (minCalibrateClassShift + i),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].calls > stats[j].calls
+	})
+
+	defaultSize := stats[0].size
+	maxSize := defaultSize
+	maxSum := uint64(float64(total) * calibrateMaxPercentile)
+	var sum uint64
+	for _, s := range stats {
+		if sum > maxSum {
+			break
+		}
+		sum += s.calls
+		if s.size > maxSize {
+			maxSize = s.size
+		}
+	}
+
+	atomic.StoreUint64(&bp.defaultSize, defaultSize)
+	atomic.StoreUint64(&bp.maxSize, maxSize)
+	atomic.StoreUint64(&bp.calibrating, 0)
+}
+
+// calibrateClass returns the calibration class of a buffer holding n bytes.
+func calibrateClass(n int) int {
+	idx := getClass(n, minCalibrateClassShift)
+	if idx >= calibrateClassCount {
+		idx = calibrateClassCount - 1
+	}
+	return idx
+}
@@ -0,0 +1,52 @@
+package pool
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestCalibratedBufferPool tests that calibration discards outlier buffers.
+func TestCalibratedBufferPool(t *testing.T) {
+	bp := NewCalibratedBufferPool()
+
+	if bp.MaxSize() != 0 {
+		t.Error("Expected an uncalibrated pool to have no max size")
+	}
+
+	small := make([]byte, 1000)
+	for i := 0; i <= calibrateCallsThreshold; i++ {
+		buf := bp.Get()
+		buf.Write(small)
+		bp.Put(buf)
+	}
+
+	if got := bp.DefaultSize(); got != 1024 {
+		t.Errorf("Expected default size 1024, got %d", got)
+	}
+	if got := bp.MaxSize(); got != 1024 {
+		t.Errorf("Expected max size 1024, got %d", got)
+	}
+
+	bp.pool.Clear()
+	bp.Put(bytes.NewBuffer(make([]byte, 1<<20)))
+	if n := idleCount(bp.pool); n != 0 {
+		t.Errorf("Expected the giant buffer to be discarded, got %d idle", n)
+	}
+}
+
+// TestCalibrateClass tests the calibration class calculation.
+func TestCalibrateClass(t *testing.T) {
+	cases := []struct {
+		n, idx int
+	}{
+		{0, 0},
+		{64, 0},
+		{65, 1},
+		{1 << 30, calibrateClassCount - 1},
+	}
+	for _, c := range cases {
+		if got := calibrateClass(c.n); got != c.idx {
+			t.Errorf("calibrateClass(%d) = %d, expected %d", c.n, got, c.idx)
+		}
+	}
+}
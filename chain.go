@@ -0,0 +1,101 @@
+package pool
+
+// TryGetter is implemented by Poolers that can report an empty pool instead
+// of creating an object, such as Pool. Chain uses it to fall through tiers.
+type TryGetter interface {
+	// TryGet retrieves an idle object and reports whether there was one.
+	TryGet() (interface{}, bool)
+}
+
+// PutChecker is implemented by Poolers that report whether they retained a
+// returned object, such as Pool. Chain uses it to pass declined objects on.
+type PutChecker interface {
+	// PutCheck returns an object and reports whether it was retained.
+	PutCheck(obj interface{}) bool
+}
+
+// ChainPool is a Pooler trying several Poolers in order, see Chain.
+type ChainPool struct {
+	pools []Pooler
+}
+
+// Chain returns a Pooler for tiered setups such as a small local pool in front
+// of a big shared one. Get tries the pools in order and takes an idle object
+// from the first that has one; pools that do not implement TryGetter end the
+// search, and the last pool creates the object if all are empty. Put returns
+// the object to the first pool, passing it on to the next while pools
+// implementing PutChecker decline it. Clear clears all pools.
+// It panics if no pool is given.
+func Chain(pools ...Pooler) *ChainPool {
+	if len(pools) == 0 {
+		panic("pool: Chain needs at least one pool")
+	}
+	return &ChainPool{pools: pools}
+}
+
+// Get retrieves an object from the first pool that has one, or creates it
+// with the last pool.
+func (c *ChainPool) Get() interface{} {
+	last := len(c.pools) - 1
+	for _, p := range c.pools[:last] {
+		tg, ok := p.(TryGetter)
+		if !ok {
+			return p.Get()
+		}
+		if obj, ok := tg.TryGet(); ok {
+			return obj
+		}
+	}
+	return c.pools[last].Get()
+}
+
+// TryGet retrieves an idle object from the first pool that has one.
+func (c *ChainPool) TryGet() (interface{}, bool) {
+	for _, p := range c.pools {
+		tg, ok := p.(TryGetter)
+		if !ok {
+			return p.Get(), true
+		}
+		if obj, ok := tg.TryGet(); ok {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+// Put returns an object to the first pool that retains it.
+// If the object is nil, it will be ignored.
+func (c *ChainPool) Put(obj interface{}) {
+	c.PutCheck(obj)
+}
+
+// PutCheck returns an object like Put and reports whether a pool retained it.
+func (c *ChainPool) PutCheck(obj interface{}) bool {
+	if obj == nil {
+		return false
+	}
+	for _, p := range c.pools {
+		pc, ok := p.(PutChecker)
+		if !ok {
+			p.Put(obj)
+			return true
+		}
+		if pc.PutCheck(obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// Clear clears all pools of the chain.
+func (c *ChainPool) Clear() {
+	for _, p := range c.pools {
+		p.Clear()
+	}
+}
+
+var (
+	_ Pooler     = (*ChainPool)(nil)
+	_ TryGetter  = (*Pool)(nil)
+	_ PutChecker = (*Pool)(nil)
+)
@@ -0,0 +1,68 @@
+package pool
+
+import "testing"
+
+// TestChainGet tests that Get falls through the tiers before creating an object.
+func TestChainGet(t *testing.T) {
+	created := map[string]int{}
+	newTier := func(name string) *Pool {
+		return predictable(NewPool(func() interface{} {
+			created[name]++
+			return new(int)
+		}))
+	}
+	local, shared := newTier("local"), newTier("shared")
+	c := Chain(local, shared)
+
+	obj := new(int)
+	shared.Put(obj)
+	if got := c.Get(); got != obj {
+		t.Error("Expected the object of the second tier")
+	}
+	c.Get()
+	if created["local"] != 0 || created["shared"] != 1 {
+		t.Errorf("Expected only the last tier to create objects, got %v", created)
+	}
+}
+
+// TestChainPut tests that Put returns objects to the first tier and passes declined ones on.
+func TestChainPut(t *testing.T) {
+	local := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	local.updateConfig(func(c *config) {
+		c.shardCap = 0
+	})
+	shared := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	c := Chain(local, shared)
+
+	if !c.PutCheck(new(int)) {
+		t.Error("Expected the object to be retained by a tier")
+	}
+	if idleCount(local) != 0 || idleCount(shared) != 1 {
+		t.Errorf("Expected the declined object in the second tier, got %d and %d", idleCount(local), idleCount(shared))
+	}
+
+	c.Clear()
+	if idleCount(shared) != 0 {
+		t.Error("Expected Clear to clear all tiers")
+	}
+}
+
+// TestTryGet tests that TryGet never creates objects.
+func TestTryGet(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		t.Error("Expected TryGet not to create an object")
+		return new(int)
+	}))
+	if _, ok := p.TryGet(); ok {
+		t.Error("Expected no object from an empty pool")
+	}
+	obj := new(int)
+	p.Put(obj)
+	if got, ok := p.TryGet(); !ok || got != obj {
+		t.Error("Expected the idle object")
+	}
+}
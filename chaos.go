@@ -0,0 +1,21 @@
+package pool
+
+import "math/rand/v2"
+
+// Race chaos mimics what sync.Pool does under the race detector: shards are
+// picked at random, one in raceChaosRatio Puts is dropped and one in
+// raceChaosRatio Gets returns a fresh object. Tests built with -race thereby
+// surface code that wrongly assumes objects are retained or reused.
+
+// One in raceChaosRatio operations is disturbed by race chaos
+const raceChaosRatio = 4
+
+// raceShardID returns a random shard ID.
+func (p *Pool) raceShardID() uint64 {
+	return rand.Uint64() & p.shardMask
+}
+
+// raceDrop reports whether race chaos decides to disturb the current operation.
+func raceDrop() bool {
+	return rand.N(raceChaosRatio) == 0
+}
@@ -0,0 +1,39 @@
+package pool
+
+import "testing"
+
+// TestRaceChaos tests that race chaos drops some Puts and returns some fresh objects.
+func TestRaceChaos(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+	p.updateConfig(func(c *config) {
+		c.raceChaos = true
+	})
+
+	const n = 1000
+	for i := 0; i < n; i++ {
+		p.Put(new(int))
+	}
+	kept := idleCount(p)
+	if kept == 0 || kept == n {
+		t.Errorf("Expected some but not all Puts to be dropped, kept %d of %d", kept, n)
+	}
+
+	p.updateConfig(func(c *config) {
+		c.shardCap = n
+	})
+	p.Clear()
+	obj := new(int)
+	fresh := 0
+	for i := 0; i < n; i++ {
+		p.shards[0].push(entry{obj: obj}, n, nil)
+		if got := p.Get(); got != obj {
+			fresh++
+		}
+		p.Clear()
+	}
+	if fresh == 0 || fresh == n {
+		t.Errorf("Expected some but not all Gets to return fresh objects, got %d of %d", fresh, n)
+	}
+}
@@ -0,0 +1,76 @@
+package pool
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+	"weak"
+)
+
+// Clock is the source of time of a pool, see WithClock.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// NewTicker returns a ticker delivering ticks every d
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks of a Clock at intervals.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered
+	C() <-chan time.Time
+	// Stop turns off the ticker
+	Stop()
+}
+
+// systemClock is the Clock reading the system time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+// systemTicker is a Ticker backed by a time.Ticker.
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
+
+// jittered returns d changed by a random fraction of up to cfg.jitter in
+// either direction, and d itself without jitter.
+func (c *config) jittered(d time.Duration) time.Duration {
+	if c.jitter <= 0 {
+		return d
+	}
+	f := 1 + c.jitter*(2*rand.Float64()-1)
+	return max(time.Duration(float64(d)*f), time.Millisecond)
+}
+
+// newTicker returns a ticker of the pool's clock for a background task
+// running every d, jittered by the configured fraction.
+func (p *Pool) newTicker(d time.Duration) Ticker {
+	cfg := p.config()
+	return cfg.clock.NewTicker(cfg.jittered(d))
+}
+
+// runTask starts a background task calling fn with p every interval. The task
+// holds p weakly, so fn must use its argument rather than capture p, and stops
+// once p is collected or closed.
+func (p *Pool) runTask(interval time.Duration, fn func(p *Pool)) {
+	wp := weak.Make(p)
+	ticker := p.newTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for range ticker.C() {
+			p := wp.Value()
+			if p == nil || atomic.LoadInt32(&p.state) == stateClosed {
+				return
+			}
+			fn(p)
+		}
+	}()
+}
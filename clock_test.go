@@ -0,0 +1,51 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// fixedClock is a Clock standing still at a given time.
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time { return c.now }
+
+func (c *fixedClock) NewTicker(d time.Duration) Ticker {
+	return systemClock{}.NewTicker(d)
+}
+
+// TestClock tests that object lifetimes are read from the configured clock.
+func TestClock(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithObjectMetadata(), WithClock(clock)))
+	obj := p.Get()
+	clock.now = clock.now.Add(time.Minute)
+	p.Put(obj)
+
+	p.Inspect(func(info ObjectInfo) {
+		if !info.Created.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected the creation time of the clock, got %v", info.Created)
+		}
+		if info.LastUsed.Sub(info.Created) != time.Minute {
+			t.Errorf("Expected the object to be used a minute later, got %v", info.LastUsed.Sub(info.Created))
+		}
+	})
+}
+
+// TestJitter tests that task intervals stay within the jitter fraction.
+func TestJitter(t *testing.T) {
+	cfg := defaultConfig()
+	if d := cfg.jittered(time.Second); d != time.Second {
+		t.Errorf("Expected no jitter by default, got %v", d)
+	}
+	WithJitter(0.2)(cfg)
+	for i := 0; i < 100; i++ {
+		if d := cfg.jittered(time.Second); d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("Expected an interval within 20%% of 1s, got %v", d)
+		}
+	}
+}
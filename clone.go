@@ -0,0 +1,23 @@
+package pool
+
+import "sync/atomic"
+
+// cloned returns a clone of the prototype proto and puts proto back into the
+// shards. proto was never checked out, so it skips the Put bookkeeping, the
+// zeroing and the sanitizer.
+func (p *Pool) cloned(proto interface{}, cfg *config) interface{} {
+	obj := cfg.clone(proto)
+	epoch := atomic.LoadUint64(&p.epoch)
+	var meta *objectMeta
+	if cfg.metadata {
+		meta = p.meta.checkin(proto, cfg.clock.Now(), epoch)
+	}
+	e := entry{obj: proto, meta: meta, epoch: epoch}
+	if cfg.sizer != nil {
+		e.size = cfg.sizer(proto)
+	}
+	if !p.restore(e, cfg) {
+		p.destroy(proto, cfg)
+	}
+	return obj
+}
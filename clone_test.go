@@ -0,0 +1,52 @@
+package pool
+
+import "testing"
+
+// TestClone tests that Get hands out clones of a single pooled prototype.
+func TestClone(t *testing.T) {
+	created := 0
+	p := predictable(NewPool(func() interface{} {
+		created++
+		return &[]string{"header"}
+	}, WithClone(func(obj interface{}) interface{} {
+		proto := *obj.(*[]string)
+		clone := append([]string(nil), proto...)
+		return &clone
+	})))
+
+	a := p.Get().(*[]string)
+	*a = append(*a, "a")
+	b := p.Get().(*[]string)
+	if created != 1 {
+		t.Errorf("Expected 1 prototype to be created, got %d", created)
+	}
+	if len(*b) != 1 || (*b)[0] != "header" {
+		t.Errorf("Expected a clean clone, got %v", *b)
+	}
+
+	if p.PutCheck(a) {
+		t.Error("Expected clones not to be retained")
+	}
+	if n := idleCount(p); n != 1 {
+		t.Errorf("Expected only the prototype to be idle, got %d", n)
+	}
+	if obj, ok := p.TryGet(); !ok || obj.(*[]string) == b {
+		t.Error("Expected TryGet to clone the prototype")
+	}
+}
+
+// TestCloneRefused tests that a prototype the shards refuse is destroyed.
+func TestCloneRefused(t *testing.T) {
+	destroyed := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithShardCap(0), WithClone(func(obj interface{}) interface{} {
+		return new(int)
+	}), WithDestructor(func(obj interface{}) {
+		destroyed++
+	})))
+	p.Get()
+	if destroyed != 1 {
+		t.Errorf("Expected the refused prototype to be destroyed, got %d", destroyed)
+	}
+}
@@ -0,0 +1,73 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+)
+
+// CloneEmpty creates a new, empty pool with the factory and the current
+// configuration of p, including its options and hooks, e.g. to give each
+// request class of a server an isolated pool with consistent settings.
+// The clone has its own stats, in-use limit and factory rate limiter, joins
+// the group of p if any with the same weight, and is not named.
+func (p *Pool) CloneEmpty() *Pool {
+	next := *p.config()
+	if next.newLimiter != nil {
+		next.newLimiter = rate.NewLimiter(next.newLimiter.Limit(), next.newLimiter.Burst())
+	}
+	if next.group != nil {
+		next.group = &groupMember{group: next.group.group, weight: next.group.weight}
+	}
+	return newPool("", p.newFunc, []Option{func(c *config) {
+		*c = next
+	}})
+}
+
+// CloneWithObjects creates a clone of p like CloneEmpty and warms it up with
+// up to n new objects from the factory, fewer if the clone cannot retain them
+// or the factory fails.
+func (p *Pool) CloneWithObjects(n int) *Pool {
+	clone := p.CloneEmpty()
+	cfg := clone.config()
+	for i := 0; i < n; i++ {
+		if !clone.addNew(cfg) {
+			break
+		}
+	}
+	return clone
+}
+
+// addNew creates a new idle object with the factory and reports whether it
+// was retained, e.g. in place of one that was lost. The objects go round-robin
+// to the shards with room, and the factory is not called once all are full.
+func (p *Pool) addNew(cfg *config) bool {
+	shardID, ok := p.roomyShard(cfg)
+	if !ok {
+		return false
+	}
+	obj, err := p.callFactory(context.Background(), cfg, nil)
+	if err != nil || obj == nil {
+		return false
+	}
+	if !p.adopt(entry{obj: obj}, shardHint{key: shardID, set: true}) {
+		p.destroy(obj, cfg)
+		return false
+	}
+	return true
+}
+
+// roomyShard returns the next shard in round-robin order whose idle objects
+// are below its capacity, or false if all shards are full.
+func (p *Pool) roomyShard(cfg *config) (uint64, bool) {
+	start := p.shardIDRand()
+	for i := range uint64(len(p.shards)) {
+		id := (start + i) & p.shardMask
+		shard := &p.shards[id]
+		if int(atomic.LoadInt32(&shard.depth)) < shard.limit(cfg) {
+			return id, true
+		}
+	}
+	return 0, false
+}
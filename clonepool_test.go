@@ -0,0 +1,56 @@
+package pool
+
+import "testing"
+
+// TestCloneEmpty tests that a clone shares the configuration but not the contents.
+func TestCloneEmpty(t *testing.T) {
+	puts := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithFIFO(), WithHooks(Hooks{
+		OnPut: func(obj interface{}) {
+			puts++
+		},
+	})))
+	p.Put(new(int))
+
+	clone := p.CloneEmpty()
+	if n := idleCount(clone); n != 0 {
+		t.Errorf("Expected the clone to be empty, got %d idle", n)
+	}
+	if !clone.config().fifo {
+		t.Error("Expected the clone to inherit the options")
+	}
+	clone.Put(clone.Get())
+	if puts != 2 {
+		t.Errorf("Expected the clone to share the hooks, got %d puts", puts)
+	}
+	if s := p.Stats(); s.Gets != 0 {
+		t.Errorf("Expected the clone to have its own stats, got %d gets on p", s.Gets)
+	}
+}
+
+// TestCloneWithObjects tests that a clone is warmed up with new objects.
+func TestCloneWithObjects(t *testing.T) {
+	created := 0
+	p := NewPool(func() interface{} {
+		created++
+		return new(int)
+	})
+
+	clone := p.CloneWithObjects(5)
+	if n := idleCount(clone); n != 5 {
+		t.Errorf("Expected 5 idle objects in the clone, got %d", n)
+	}
+	if created != 5 {
+		t.Errorf("Expected 5 objects to be created, got %d", created)
+	}
+
+	g := NewGroup(2)
+	p = NewPool(func() interface{} {
+		return new(int)
+	}, WithGroup(g, 1))
+	if n := idleCount(p.CloneWithObjects(5)); n != 2 {
+		t.Errorf("Expected the clone to be bounded by the group, got %d idle", n)
+	}
+}
@@ -0,0 +1,78 @@
+// Command poolbench benchmarks the pooling backends and prints a report.
+//
+//	poolbench -sizes 64,4096 -goroutines 1,8,64 -hold 0,1us -ops 100000
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ongniud/pool"
+	"github.com/ongniud/pool/poolbench"
+)
+
+func main() {
+	backends := flag.String("backends", "sharded,sync,hybrid,noop", "comma-separated backends to compare")
+	sizes := flag.String("sizes", "64,4096", "comma-separated object sizes in bytes")
+	goroutines := flag.String("goroutines", "1,8", "comma-separated goroutine counts")
+	holds := flag.String("hold", "0", "comma-separated hold times, e.g. 0,1us")
+	ops := flag.Int("ops", 100000, "Get/Put pairs per goroutine")
+	flag.Parse()
+
+	cfg := poolbench.Config{Ops: *ops}
+	for _, b := range split(*backends) {
+		cfg.Backends = append(cfg.Backends, pool.Backend(b))
+	}
+	var err error
+	if cfg.Sizes, err = ints(*sizes); err != nil {
+		fail(err)
+	}
+	if cfg.Goroutines, err = ints(*goroutines); err != nil {
+		fail(err)
+	}
+	for _, s := range split(*holds) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			fail(err)
+		}
+		cfg.HoldTimes = append(cfg.HoldTimes, d)
+	}
+
+	if err := poolbench.Run(cfg).WriteText(os.Stdout); err != nil {
+		fail(err)
+	}
+}
+
+// split splits a comma-separated flag value.
+func split(s string) []string {
+	var parts []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// ints parses a comma-separated list of integers.
+func ints(s string) ([]int, error) {
+	var out []int
+	for _, p := range split(s) {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// fail prints err and exits.
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "poolbench:", err)
+	os.Exit(2)
+}
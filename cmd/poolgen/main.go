@@ -0,0 +1,144 @@
+// Command poolgen generates a strongly typed wrapper around pool.Pool for a
+// named type, with GetT and PutT methods that call the type's Reset method on
+// Put. The generated code does not use generics.
+//
+//	//go:generate poolgen -type Buffer
+//
+// writes buffer_pool.go with BufferPool, NewBufferPool, GetBuffer and PutBuffer
+// to the package of the go:generate directive.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Config describes the wrapper to generate.
+type Config struct {
+	// Package is the name of the package of the generated file
+	Package string
+	// Type is the name of the pooled type
+	Type string
+	// Name is the name of the wrapper type, Type+"Pool" if empty
+	Name string
+	// Value pools values of Type instead of pointers to it
+	Value bool
+	// Reset calls the Reset method of objects on Put
+	Reset bool
+}
+
+func main() {
+	typ := flag.String("type", "", "name of the pooled type (required)")
+	name := flag.String("name", "", "name of the wrapper type (default <type>Pool)")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file (default $GOPACKAGE)")
+	output := flag.String("output", "", "output file (default <type>_pool.go)")
+	value := flag.Bool("value", false, "pool values of the type instead of pointers")
+	reset := flag.Bool("reset", true, "call Reset on objects returned with Put")
+	flag.Parse()
+
+	src, err := generate(Config{Package: *pkg, Type: *typ, Name: *name, Value: *value, Reset: *reset})
+	if err != nil {
+		fail(err)
+	}
+	if *output == "" {
+		*output = snake(*typ) + "_pool.go"
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fail(err)
+	}
+}
+
+// generate returns the formatted source of the wrapper described by cfg.
+func generate(cfg Config) ([]byte, error) {
+	if cfg.Type == "" {
+		return nil, errors.New("-type is required")
+	}
+	if cfg.Package == "" {
+		return nil, errors.New("-package is required outside of go generate")
+	}
+	if cfg.Name == "" {
+		cfg.Name = cfg.Type + "Pool"
+	}
+	var buf bytes.Buffer
+	if err := wrapper.Execute(&buf, cfg); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// wrapper is the template of the generated file.
+var wrapper = template.Must(template.New("wrapper").Parse(`// Code generated by poolgen; DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/ongniud/pool"
+
+{{$elem := printf "*%s" .Type}}{{if .Value}}{{$elem = .Type}}{{end -}}
+// {{.Name}} is a pool of {{$elem}}.
+type {{.Name}} struct {
+	pool *pool.Pool
+}
+
+// New{{.Name}} creates a new pool of {{$elem}}.
+func New{{.Name}}(opts ...pool.Option) *{{.Name}} {
+	return &{{.Name}}{
+		pool: pool.NewPool(func() interface{} {
+			{{if .Value}}var obj {{.Type}}
+			return obj{{else}}return new({{.Type}}){{end}}
+		}, opts...),
+	}
+}
+
+// Get{{.Type}} retrieves a {{$elem}} from the pool.
+func (p *{{.Name}}) Get{{.Type}}() {{$elem}} {
+	return p.pool.Get().({{$elem}})
+}
+
+// Put{{.Type}} {{if .Reset}}resets a {{$elem}} and returns it{{else}}returns a {{$elem}}{{end}} to the pool.
+func (p *{{.Name}}) Put{{.Type}}(obj {{$elem}}) {
+	{{if not .Value}}if obj == nil {
+		return
+	}
+	{{end}}{{if .Reset}}obj.Reset()
+	{{end}}p.pool.Put(obj)
+}
+
+// Clear clears all objects from the pool.
+func (p *{{.Name}}) Clear() {
+	p.pool.Clear()
+}
+
+// Pool returns the underlying pool.
+func (p *{{.Name}}) Pool() *pool.Pool {
+	return p.pool
+}
+`))
+
+// snake converts a Go identifier to snake case, e.g. HTTPBuffer to http_buffer.
+func snake(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// fail prints err and exits.
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "poolgen:", err)
+	os.Exit(2)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// TestGenerate tests that the generated wrapper parses and resets objects on Put.
+func TestGenerate(t *testing.T) {
+	src, err := generate(Config{Package: "foo", Type: "Buffer", Reset: true})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "buffer_pool.go", src, 0); err != nil {
+		t.Fatalf("Expected valid Go source, got %v:\n%s", err, src)
+	}
+	for _, want := range []string{"type BufferPool struct", "func NewBufferPool(", "func (p *BufferPool) GetBuffer() *Buffer", "obj.Reset()"} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected the source to contain %q:\n%s", want, src)
+		}
+	}
+}
+
+// TestGenerateValue tests wrappers of values without Reset.
+func TestGenerateValue(t *testing.T) {
+	src, err := generate(Config{Package: "foo", Type: "Point", Name: "Points", Value: true})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if !strings.Contains(string(src), "func (p *Points) PutPoint(obj Point)") || strings.Contains(string(src), "Reset") {
+		t.Errorf("Unexpected source:\n%s", src)
+	}
+	if _, err := generate(Config{Package: "foo"}); err == nil {
+		t.Error("Expected an error without a type")
+	}
+}
+
+// TestSnake tests the conversion of type names to file names.
+func TestSnake(t *testing.T) {
+	for in, want := range map[string]string{"Buffer": "buffer", "HTTPBuffer": "http_buffer", "myType": "my_type"} {
+		if got := snake(in); got != want {
+			t.Errorf("snake(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
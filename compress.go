@@ -0,0 +1,152 @@
+package pool
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+const (
+	// Lowest compression level, flate.HuffmanOnly
+	minCompressLevel = flate.HuffmanOnly
+	// Highest compression level, flate.BestCompression
+	maxCompressLevel = flate.BestCompression
+	// Count of compression level classes
+	compressLevelCount = maxCompressLevel - minCompressLevel + 1
+)
+
+// CompressFormat is the stream format of a CompressWriterPool.
+type CompressFormat int
+
+const (
+	// FormatGzip writes gzip streams with compress/gzip
+	FormatGzip CompressFormat = iota
+	// FormatFlate writes raw DEFLATE streams with compress/flate
+	FormatFlate
+)
+
+// compressor is the interface shared by gzip and flate writers.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// CompressWriter is a pooled gzip or flate writer.
+// It remembers the first error of the underlying writer, after which the
+// writer is discarded on Put instead of being reused.
+type CompressWriter struct {
+	w     compressor
+	level int
+	err   error
+}
+
+// Write compresses p to the destination writer.
+func (cw *CompressWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.fail(err)
+	return n, err
+}
+
+// Flush flushes the pending compressed data to the destination writer.
+func (cw *CompressWriter) Flush() error {
+	err := cw.w.Flush()
+	cw.fail(err)
+	return err
+}
+
+// Close flushes the pending data and writes the end of the stream. It does
+// not close the destination writer.
+func (cw *CompressWriter) Close() error {
+	err := cw.w.Close()
+	cw.fail(err)
+	return err
+}
+
+// Level returns the compression level of the writer.
+func (cw *CompressWriter) Level() int {
+	return cw.level
+}
+
+// Err returns the first error of the writer since it was retrieved.
+func (cw *CompressWriter) Err() error {
+	return cw.err
+}
+
+// fail records err if it is the first error of the writer.
+func (cw *CompressWriter) fail(err error) {
+	if err != nil && cw.err == nil {
+		cw.err = err
+	}
+}
+
+// CompressWriterPool is a pool of gzip or flate writers split into classes by
+// compression level. The writers are expensive to create, a flate writer
+// allocates hundreds of kilobytes, and easy to corrupt when reused by hand:
+// Get resets a writer to its destination, Put resets it to io.Discard so it
+// does not keep the destination alive, and writers that failed are discarded.
+type CompressWriterPool struct {
+	classes [compressLevelCount]*Pool
+}
+
+// NewCompressWriterPool creates a new pool of writers of format.
+// opts customize the pool of every level.
+func NewCompressWriterPool(format CompressFormat, opts ...Option) *CompressWriterPool {
+	cp := &CompressWriterPool{}
+	for i := range cp.classes {
+		level := minCompressLevel + i
+		cp.classes[i] = NewPool(func() interface{} {
+			return &CompressWriter{w: newCompressor(format, level), level: level}
+		}, opts...)
+	}
+	return cp
+}
+
+// newCompressor creates a writer of format at a valid level.
+func newCompressor(format CompressFormat, level int) compressor {
+	if format == FormatFlate {
+		fw, _ := flate.NewWriter(io.Discard, level)
+		return fw
+	}
+	zw, _ := gzip.NewWriterLevel(io.Discard, level)
+	return zw
+}
+
+// Get retrieves a writer compressing to w at level, one of the levels of
+// compress/flate from HuffmanOnly to BestCompression.
+// Callers Close the writer to finish the stream before they Put it.
+func (cp *CompressWriterPool) Get(w io.Writer, level int) (*CompressWriter, error) {
+	if level < minCompressLevel || level > maxCompressLevel {
+		return nil, fmt.Errorf("pool: invalid compression level %d", level)
+	}
+	obj, err := cp.classes[level-minCompressLevel].GetE()
+	if err != nil {
+		return nil, err
+	}
+	cw := obj.(*CompressWriter)
+	cw.w.Reset(w)
+	return cw, nil
+}
+
+// Put returns a writer to the class of its level, or discards it if it
+// failed since it was retrieved.
+func (cp *CompressWriterPool) Put(cw *CompressWriter) {
+	if cw == nil {
+		return
+	}
+	p := cp.classes[cw.level-minCompressLevel]
+	if cw.err != nil {
+		p.Discard(cw)
+		return
+	}
+	cw.w.Reset(io.Discard)
+	p.Put(cw)
+}
+
+// Clear clears all writers from the pool.
+func (cp *CompressWriterPool) Clear() {
+	for _, p := range cp.classes {
+		p.Clear()
+	}
+}
@@ -0,0 +1,88 @@
+package pool
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+)
+
+// failingWriter is a writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("broken pipe") }
+
+// TestCompressWriterPool tests that pooled writers produce valid streams after reuse.
+func TestCompressWriterPool(t *testing.T) {
+	cp := NewCompressWriterPool(FormatGzip)
+	for _, p := range cp.classes {
+		predictable(p)
+	}
+	var first *CompressWriter
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		cw, err := cp.Get(&buf, gzip.BestSpeed)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if i == 0 {
+			first = cw
+		} else if cw != first {
+			t.Error("Expected the writer to be reused")
+		}
+		cw.Write([]byte("hello"))
+		cw.Close()
+		cp.Put(cw)
+
+		zr, err := gzip.NewReader(&buf)
+		if err != nil {
+			t.Fatalf("Expected a valid gzip stream, got %v", err)
+		}
+		if b, _ := io.ReadAll(zr); string(b) != "hello" {
+			t.Errorf("Expected %q, got %q", "hello", b)
+		}
+	}
+	if _, err := cp.Get(io.Discard, 42); err == nil {
+		t.Error("Expected an error for an invalid level")
+	}
+}
+
+// TestCompressWriterPoolLevels tests that writers are kept apart by level.
+func TestCompressWriterPoolLevels(t *testing.T) {
+	cp := NewCompressWriterPool(FormatFlate)
+	for _, p := range cp.classes {
+		predictable(p)
+	}
+	cw, _ := cp.Get(io.Discard, flate.BestSpeed)
+	cp.Put(cw)
+
+	if got, _ := cp.Get(io.Discard, flate.BestCompression); got == cw || got.Level() != flate.BestCompression {
+		t.Error("Expected a writer of the requested level")
+	}
+	if got, _ := cp.Get(io.Discard, flate.BestSpeed); got != cw {
+		t.Error("Expected the writer of the same level to be reused")
+	}
+}
+
+// TestCompressWriterPoolBroken tests that failed writers are discarded.
+func TestCompressWriterPoolBroken(t *testing.T) {
+	cp := NewCompressWriterPool(FormatGzip)
+	for _, p := range cp.classes {
+		predictable(p)
+	}
+	cw, _ := cp.Get(failingWriter{}, gzip.DefaultCompression)
+	cw.Write(bytes.Repeat([]byte("x"), 1<<16))
+	if err := cw.Close(); err == nil {
+		t.Fatal("Expected the write to the broken destination to fail")
+	}
+	if cw.Err() == nil {
+		t.Error("Expected the writer to remember its error")
+	}
+	cp.Put(cw)
+
+	if got, _ := cp.Get(io.Discard, gzip.DefaultCompression); got == cw {
+		t.Error("Expected the broken writer not to be reused")
+	}
+}
@@ -0,0 +1,187 @@
+package pool
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// config holds the tunable settings of a Pool.
+// A config is immutable once published; changes are made by copying the
+// current snapshot, modifying the copy and swapping it in atomically.
+type config struct {
+	// Capacity of each shard
+	shardCap int
+	// Maximum number of shards to steal from when the preferred shard is empty
+	stealShardCnt int
+	// Time after which a checked-out object is reported as leaked, zero disables leak detection
+	leakTimeout time.Duration
+	// Function receiving leak reports
+	leakHandler func(Leak)
+	// Function called when an object already in the pool is Put again, nil disables the check
+	doublePutHandler func(obj interface{})
+	// Function called when a sanitized object was modified after Put, nil disables the sanitizer
+	sanitizeHandler func(obj interface{})
+	// Randomize shard selection, Puts and Gets, enabled when built with -race
+	raceChaos bool
+	// Report leases that are garbage collected without being released
+	leaseFinalizer bool
+	// Put the object of a finalized lease back into the pool
+	leaseReclaim bool
+	// Replace the object of a finalized lease that is not reclaimed with a new one
+	leaseReplace bool
+	// Context-aware factory used instead of newFunc, nil if not set
+	factoryCtx func(ctx context.Context) (interface{}, error)
+	// Consecutive factory failures that open the circuit breaker, zero disables it
+	breakerFailures int
+	// Time the circuit breaker stays open
+	breakerCoolDown time.Duration
+	// Max number of objects checked out at the same time, zero means unlimited
+	maxInUse int
+	// Wait for an object to be returned instead of failing when maxInUse is reached
+	maxInUseWait bool
+	// Function releasing objects that leave the pool for good, nil if not set
+	destructor func(obj interface{})
+	// Track creation time, last-used time and use count of every object
+	metadata bool
+	// Policy choosing the idle objects to reuse and evict, nil means LIFO
+	policy EvictionPolicy
+	// Hand out the least recently returned object first
+	fifo bool
+	// Max total size of the idle objects, zero means unlimited
+	maxMemory int64
+	// Function reporting the size of an object, nil if not set
+	sizer Sizer
+	// Fraction of idle objects destroyed after every GC cycle, zero disables GC trimming
+	gcTrimFraction float64
+	// Functions called on lifecycle events
+	hooks Hooks
+	// Value of the pprof label "pool" set while the factory runs, empty for none
+	profileLabel string
+	// Bounds of the adaptive shard capacity, nil if disabled
+	autoTune *AutoTuneConfig
+	// Adapt the number of shards to steal from to the steal success rate
+	adaptiveSteal bool
+	// Seed of the deterministic shard sequence, nil for stack-address selection
+	shardSeed *uint64
+	// What happens to objects returned to a full shard
+	overflow Overflow
+	// Return objects to the shard they were taken from
+	affinity bool
+	// Capacity of each shard's hot tier, zero to disable it
+	hotCap int
+	// Age after which hot objects are demoted to the cold tier
+	hotAge time.Duration
+	// Wipes the contents of objects before they are retained, nil to keep them
+	zeroFunc func(obj interface{})
+	// Clones the pooled prototypes handed out by Get, nil to hand out the objects
+	clone func(obj interface{}) interface{}
+	// Limits the rate of factory calls, nil for no limit
+	newLimiter *rate.Limiter
+	// Wait for the rate limiter instead of failing with ErrRateLimited
+	newLimitWait bool
+	// Membership in a Group sharing an idle budget, nil for none
+	group *groupMember
+	// Factory receiving the arguments of GetWith, nil to use the pool's
+	argsFactory func(args any) interface{}
+	// Time returned objects are held aside before they may be reused, zero to disable it
+	quarantine time.Duration
+	// Logger receiving the pool's events, nil to log nothing
+	logger *slog.Logger
+	// Levels of the logged events, nil for DefaultLogLevels
+	logLevels *LogLevels
+	// Reports slow waits and factory calls of Gets, nil if not set
+	slow *slowHandler
+	// Source of time of lifetimes, ages and background tasks
+	clock Clock
+	// Max fraction by which the intervals of background tasks are randomized
+	jitter float64
+	// Number of objects each shard accepts above its capacity, zero for none
+	softCap int
+	// Time objects above the shard capacity stay idle
+	softTTL time.Duration
+	// Number of idle objects the background maintenance converges toward, zero to disable it
+	retentionTarget int
+	// Interval of the background refresh of idle objects, zero to disable it
+	refreshInterval time.Duration
+	// Function refreshing an idle object, see WithRefresh
+	refresh func(obj interface{}) error
+}
+
+// defaultConfig returns the configuration used by NewPool.
+func defaultConfig() *config {
+	c := &config{
+		shardCap:      shardCap,
+		stealShardCnt: stealShardCnt,
+		leakHandler:   logLeak,
+		raceChaos:     raceEnabled,
+		clock:         systemClock{},
+	}
+	if sanitizeDefault {
+		c.sanitizeHandler = panicUseAfterPut
+	}
+	return c
+}
+
+// Config is a read-only copy of the configuration of a Pool.
+type Config struct {
+	ShardCount    int
+	ShardCap      int
+	StealShardCnt int
+	LeakTimeout   time.Duration
+	// DetectDoublePut reports whether double-Put detection is enabled
+	DetectDoublePut bool
+	// Sanitize reports whether the sanitizer is enabled
+	Sanitize bool
+	// MaxInUse is the max number of objects checked out at the same time, zero means unlimited
+	MaxInUse int
+	// FIFO reports whether shards hand out the least recently returned object first
+	FIFO bool
+	// MaxMemory is the max total size of the idle objects in bytes, zero means unlimited
+	MaxMemory int64
+}
+
+// View is a read-only view of a Pool.
+type View struct {
+	Config Config
+}
+
+// config returns the current configuration snapshot.
+// The returned value must not be modified.
+func (p *Pool) config() *config {
+	return p.cfg.Load()
+}
+
+// updateConfig applies fn to a copy of the current configuration and
+// publishes the copy. Concurrent updates are serialized through CAS, so
+// readers always observe a complete snapshot.
+func (p *Pool) updateConfig(fn func(c *config)) {
+	for {
+		old := p.cfg.Load()
+		c := *old
+		fn(&c)
+		if p.cfg.CompareAndSwap(old, &c) {
+			return
+		}
+	}
+}
+
+// View returns a read-only view of the pool.
+func (p *Pool) View() View {
+	c := p.config()
+	return View{
+		Config: Config{
+			ShardCount:      len(p.shards),
+			ShardCap:        c.shardCap,
+			StealShardCnt:   c.stealShardCnt,
+			LeakTimeout:     c.leakTimeout,
+			DetectDoublePut: c.doublePutHandler != nil,
+			Sanitize:        c.sanitizeHandler != nil,
+			MaxInUse:        c.maxInUse,
+			FIFO:            c.fifo,
+			MaxMemory:       c.maxMemory,
+		},
+	}
+}
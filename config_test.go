@@ -0,0 +1,41 @@
+package pool
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestView tests that View reports the default configuration.
+func TestView(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+
+	cfg := p.View().Config
+	if cfg.ShardCount != shardCount || cfg.ShardCap != shardCap || cfg.StealShardCnt != stealShardCnt {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
+
+// TestUpdateConfig tests that concurrent config updates are not lost.
+func TestUpdateConfig(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.updateConfig(func(c *config) {
+				c.shardCap++
+			})
+		}()
+	}
+	wg.Wait()
+
+	if got := p.View().Config.ShardCap; got != shardCap+100 {
+		t.Errorf("Expected shard cap %d, got %d", shardCap+100, got)
+	}
+}
@@ -0,0 +1,75 @@
+package pool
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// DebugHandler returns an HTTP handler that renders the pool's configuration,
+// stats and a summary of the contents of each shard as plain text, e.g. to be
+// mounted under /debug/pool. If leak detection is enabled, it also lists the
+// outstanding objects with the stack traces of their Gets.
+func (p *Pool) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		p.writeDebug(w, p.config().clock.Now())
+	})
+}
+
+// writeDebug writes the debug report of the pool to w.
+func (p *Pool) writeDebug(w io.Writer, now time.Time) {
+	st := p.Stats()
+	fmt.Fprintf(w, "config: %+v\n\n", p.View().Config)
+	fmt.Fprintf(w, "gets: %d  puts: %d  hits: %d  misses: %d  steals: %d  hit rate: %.3f\n",
+		st.Gets, st.Puts, st.Hits, st.Misses, st.Steals, st.HitRate())
+	fmt.Fprintf(w, "idle: %d  in use: %d  retained bytes: %d  imbalance: %.2f\n",
+		st.Idle, st.InUse, p.RetainedBytes(), st.Imbalance())
+	if st.Waits.Count > 0 {
+		fmt.Fprintf(w, "waits: %d  mean: %s  p50: %s  p99: %s\n",
+			st.Waits.Count, st.Waits.Mean(), st.Waits.Quantile(0.5), st.Waits.Quantile(0.99))
+	}
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "shard\tidle\tgets\thit rate\tsteals\tputs\tcontents")
+	for i, sh := range st.Shards {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%.3f\t%d\t%d\t%s\n",
+			i, sh.Idle, sh.Gets, sh.HitRate(), sh.Steals, sh.Puts, p.shardContents(i))
+	}
+	tw.Flush()
+
+	if p.config().leakTimeout > 0 {
+		leaks := p.leaks.snapshot()
+		fmt.Fprintf(w, "\noutstanding: %d\n", len(leaks))
+		for _, l := range leaks {
+			fmt.Fprintf(w, "\n%T checked out %s ago by goroutine %d\n%s", l.Object, now.Sub(l.CheckedOut).Round(time.Millisecond), l.Goroutine, l.Stack)
+		}
+	}
+}
+
+// shardContents summarizes the types of the idle objects of shard i.
+func (p *Pool) shardContents(i int) string {
+	shard := &p.shards[i]
+	counts := make(map[string]int)
+	shard.mu.Lock()
+	shard.eachLocked(func(e entry) bool {
+		counts[fmt.Sprintf("%T", e.obj)]++
+		return true
+	})
+	shard.unlock()
+
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for j, t := range types {
+		types[j] = fmt.Sprintf("%s×%d", t, counts[t])
+	}
+	return strings.Join(types, " ")
+}
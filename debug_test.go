@@ -0,0 +1,44 @@
+package pool
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDebugHandler tests that the debug handler renders stats, shards and outstanding objects.
+func TestDebugHandler(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithLeakDetection(time.Hour)))
+	p.Put(p.Get())
+	p.Get()
+
+	rec := httptest.NewRecorder()
+	p.DebugHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/pool", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Expected plain text, got %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"ShardCount:16", "gets: 2", "in use: 1", "shard", "outstanding: 1", "*int checked out", "TestDebugHandler"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the report to contain %q, got\n%s", want, body)
+		}
+	}
+}
+
+// TestShardContents tests the summary of the idle objects of a shard.
+func TestShardContents(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+	p.shards[0].push(entry{obj: new(int)}, shardCap, nil)
+	p.shards[0].push(entry{obj: new(int)}, shardCap, nil)
+	p.shards[0].push(entry{obj: "s"}, shardCap, nil)
+
+	if got := p.shardContents(0); got != "*int×2 string×1" {
+		t.Errorf("Unexpected contents %q", got)
+	}
+}
@@ -0,0 +1,157 @@
+// Package decorator provides composable wrappers that add instrumentation to
+// any pool.Pooler, so the core pools stay lean:
+//
+//	p := decorator.WithTracing(decorator.WithLogging(decorator.WithMetrics(pl), logger))
+package decorator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/trace"
+	"sync/atomic"
+
+	"github.com/ongniud/pool"
+)
+
+// Metrics is a snapshot of the counters of a MetricsPooler.
+type Metrics struct {
+	// Gets made through the wrapper
+	Gets uint64
+	// Puts of non-nil objects made through the wrapper
+	Puts uint64
+	// Clears made through the wrapper
+	Clears uint64
+	// Gets minus Puts, i.e. the objects currently checked out
+	InUse int64
+}
+
+// MetricsPooler is a Pooler that counts the calls made through it.
+type MetricsPooler struct {
+	pool.Pooler
+	gets   uint64
+	puts   uint64
+	clears uint64
+}
+
+// WithMetrics wraps p so that its Gets, Puts and Clears are counted.
+func WithMetrics(p pool.Pooler) *MetricsPooler {
+	return &MetricsPooler{Pooler: p}
+}
+
+// Get retrieves an object from the wrapped Pooler.
+func (m *MetricsPooler) Get() interface{} {
+	atomic.AddUint64(&m.gets, 1)
+	return m.Pooler.Get()
+}
+
+// Put returns an object to the wrapped Pooler.
+func (m *MetricsPooler) Put(obj interface{}) {
+	if obj != nil {
+		atomic.AddUint64(&m.puts, 1)
+	}
+	m.Pooler.Put(obj)
+}
+
+// Clear clears the wrapped Pooler.
+func (m *MetricsPooler) Clear() {
+	atomic.AddUint64(&m.clears, 1)
+	m.Pooler.Clear()
+}
+
+// Metrics returns a snapshot of the counters.
+func (m *MetricsPooler) Metrics() Metrics {
+	gets := atomic.LoadUint64(&m.gets)
+	puts := atomic.LoadUint64(&m.puts)
+	return Metrics{
+		Gets:   gets,
+		Puts:   puts,
+		Clears: atomic.LoadUint64(&m.clears),
+		InUse:  int64(gets) - int64(puts),
+	}
+}
+
+// LoggingPooler is a Pooler that logs the calls made through it.
+type LoggingPooler struct {
+	pool.Pooler
+	logger *slog.Logger
+}
+
+// WithLogging wraps p so that its Gets, Puts and Clears are logged to logger
+// at debug level. A nil logger uses slog.Default().
+func WithLogging(p pool.Pooler, logger *slog.Logger) *LoggingPooler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LoggingPooler{Pooler: p, logger: logger}
+}
+
+// Get retrieves an object from the wrapped Pooler.
+func (l *LoggingPooler) Get() interface{} {
+	obj := l.Pooler.Get()
+	l.log("pool get", obj)
+	return obj
+}
+
+// Put returns an object to the wrapped Pooler.
+func (l *LoggingPooler) Put(obj interface{}) {
+	l.log("pool put", obj)
+	l.Pooler.Put(obj)
+}
+
+// Clear clears the wrapped Pooler.
+func (l *LoggingPooler) Clear() {
+	l.logger.Debug("pool clear")
+	l.Pooler.Clear()
+}
+
+// log logs msg with the type of obj, skipping the formatting if debug is disabled.
+func (l *LoggingPooler) log(msg string, obj interface{}) {
+	ctx := context.Background()
+	if l.logger.Enabled(ctx, slog.LevelDebug) {
+		l.logger.DebugContext(ctx, msg, slog.String("type", fmt.Sprintf("%T", obj)))
+	}
+}
+
+// TracingPooler is a Pooler that marks the calls made through it as
+// runtime/trace regions, so they show up in `go tool trace`.
+type TracingPooler struct {
+	pool.Pooler
+}
+
+// WithTracing wraps p so that its Gets, Puts and Clears are traced as the
+// regions pool.Get, pool.Put and pool.Clear while an execution trace is running.
+func WithTracing(p pool.Pooler) *TracingPooler {
+	return &TracingPooler{Pooler: p}
+}
+
+// Get retrieves an object from the wrapped Pooler.
+func (t *TracingPooler) Get() interface{} {
+	if !trace.IsEnabled() {
+		return t.Pooler.Get()
+	}
+	defer trace.StartRegion(context.Background(), "pool.Get").End()
+	return t.Pooler.Get()
+}
+
+// Put returns an object to the wrapped Pooler.
+func (t *TracingPooler) Put(obj interface{}) {
+	if !trace.IsEnabled() {
+		t.Pooler.Put(obj)
+		return
+	}
+	defer trace.StartRegion(context.Background(), "pool.Put").End()
+	t.Pooler.Put(obj)
+}
+
+// Clear clears the wrapped Pooler.
+func (t *TracingPooler) Clear() {
+	defer trace.StartRegion(context.Background(), "pool.Clear").End()
+	t.Pooler.Clear()
+}
+
+var (
+	_ pool.Pooler = (*MetricsPooler)(nil)
+	_ pool.Pooler = (*LoggingPooler)(nil)
+	_ pool.Pooler = (*TracingPooler)(nil)
+)
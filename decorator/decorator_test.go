@@ -0,0 +1,63 @@
+package decorator
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/ongniud/pool"
+)
+
+// TestWithMetrics tests that calls are counted.
+func TestWithMetrics(t *testing.T) {
+	m := WithMetrics(pool.NewNoopPool(func() interface{} {
+		return new(int)
+	}))
+	obj := m.Get()
+	m.Get()
+	m.Put(obj)
+	m.Put(nil)
+	m.Clear()
+
+	want := Metrics{Gets: 2, Puts: 1, Clears: 1, InUse: 1}
+	if got := m.Metrics(); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+// TestWithLogging tests that calls are logged at debug level.
+func TestWithLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	l := WithLogging(pool.NewNoopPool(func() interface{} {
+		return new(int)
+	}), logger)
+	l.Put(l.Get())
+	l.Clear()
+
+	out := buf.String()
+	for _, want := range []string{`msg="pool get" type=*int`, `msg="pool put" type=*int`, `msg="pool clear"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the log to contain %q, got\n%s", want, out)
+		}
+	}
+}
+
+// TestChain tests that wrappers compose and still delegate to the pool.
+func TestChain(t *testing.T) {
+	m := WithMetrics(pool.NewNoopPool(func() interface{} {
+		return new(int)
+	}))
+	var p pool.Pooler = WithTracing(WithLogging(m, slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))))
+	obj := p.Get()
+	if _, ok := obj.(*int); !ok {
+		t.Fatalf("Expected *int, got %T", obj)
+	}
+	p.Put(obj)
+	p.Clear()
+
+	if got := m.Metrics(); got.Gets != 1 || got.Puts != 1 || got.Clears != 1 {
+		t.Errorf("Expected every call to reach the innermost wrapper, got %+v", got)
+	}
+}
@@ -0,0 +1,24 @@
+package pool
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// shardSequence is a seeded sequence of shard IDs, see WithDeterministicSharding.
+type shardSequence struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// newShardSequence returns the sequence of shard IDs for seed.
+func newShardSequence(seed uint64) *shardSequence {
+	return &shardSequence{rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// next returns the next shard ID of the sequence, masked with mask.
+func (s *shardSequence) next(mask uint64) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Uint64() & mask
+}
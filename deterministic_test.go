@@ -0,0 +1,30 @@
+package pool
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDeterministicSharding tests that pools with the same seed place objects identically.
+func TestDeterministicSharding(t *testing.T) {
+	run := func(seed uint64) Stats {
+		p := NewPool(func() interface{} {
+			return new(int)
+		}, WithDeterministicSharding(seed))
+		for i := 0; i < 100; i++ {
+			obj := p.Get()
+			if i%3 != 0 {
+				p.Put(obj)
+			}
+		}
+		return p.Stats()
+	}
+
+	a, b := run(42), run(42)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("Expected identical stats for the same seed, got\n%+v\n%+v", a, b)
+	}
+	if c := run(7); reflect.DeepEqual(a.Shards, c.Shards) {
+		t.Error("Expected a different placement for another seed")
+	}
+}
@@ -0,0 +1,68 @@
+package pool
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// idleTracker tracks the identity of the objects currently held by the shards.
+// The zero value is ready to use.
+type idleTracker struct {
+	count int64
+	mu    sync.Mutex
+	ids   map[uintptr]struct{}
+}
+
+// tracking reports whether any object is currently tracked.
+func (t *idleTracker) tracking() bool {
+	return atomic.LoadInt64(&t.count) > 0
+}
+
+// add starts tracking obj. If obj is already tracked, it calls handler and returns false.
+func (t *idleTracker) add(obj interface{}, handler func(obj interface{})) bool {
+	id, ok := objectID(obj)
+	if !ok {
+		return true
+	}
+	t.mu.Lock()
+	if _, dup := t.ids[id]; dup {
+		t.mu.Unlock()
+		handler(obj)
+		return false
+	}
+	if t.ids == nil {
+		t.ids = make(map[uintptr]struct{})
+	}
+	t.ids[id] = struct{}{}
+	atomic.AddInt64(&t.count, 1)
+	t.mu.Unlock()
+	return true
+}
+
+// remove stops tracking obj.
+func (t *idleTracker) remove(obj interface{}) {
+	id, ok := objectID(obj)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	if _, ok := t.ids[id]; ok {
+		delete(t.ids, id)
+		atomic.AddInt64(&t.count, -1)
+	}
+	t.mu.Unlock()
+}
+
+// reset stops tracking all objects.
+func (t *idleTracker) reset() {
+	t.mu.Lock()
+	t.ids = nil
+	atomic.StoreInt64(&t.count, 0)
+	t.mu.Unlock()
+}
+
+// panicDoublePut is the default double-Put handler.
+func panicDoublePut(obj interface{}) {
+	panic(fmt.Sprintf("pool: object %T %p was Put twice", obj, obj))
+}
@@ -0,0 +1,49 @@
+package pool
+
+import "testing"
+
+// TestDoublePutDetection tests that a second Put of the same object is reported and dropped.
+func TestDoublePutDetection(t *testing.T) {
+	var dups []interface{}
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithDoublePutDetection(func(obj interface{}) {
+		dups = append(dups, obj)
+	})))
+
+	obj := p.Get()
+	p.Put(obj)
+	p.Put(obj)
+
+	if len(dups) != 1 || dups[0] != obj {
+		t.Errorf("Expected one double Put report, got %v", dups)
+	}
+	if n := idleCount(p); n != 1 {
+		t.Errorf("Expected the duplicate to be dropped, got %d idle", n)
+	}
+
+	// A Get in between makes the next Put legal again
+	if got := p.Get(); got != obj {
+		t.Fatal("Expected Get to return the pooled object")
+	}
+	p.Put(obj)
+	if len(dups) != 1 {
+		t.Error("Expected no report after an intervening Get")
+	}
+}
+
+// TestDoublePutPanic tests that the default handler panics.
+func TestDoublePutPanic(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithDoublePutDetection(nil)))
+
+	obj := new(int)
+	p.Put(obj)
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on double Put")
+		}
+	}()
+	p.Put(obj)
+}
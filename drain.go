@@ -0,0 +1,95 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Pool states
+const (
+	stateOpen int32 = iota
+	stateDraining
+	stateClosed
+)
+
+// Interval at which Drain checks for outstanding objects
+const drainPollInterval = 5 * time.Millisecond
+
+// Drain shuts the pool down gracefully.
+// It immediately stops handing out objects (Get fails with ErrPoolClosed), waits
+// until every checked-out object has been returned or ctx is done, and then
+// destroys all idle objects with the destructor. Objects returned during or
+// after Drain are destroyed instead of retained.
+// It returns ctx.Err() if ctx was done before all objects were returned.
+func (p *Pool) Drain(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&p.state, stateOpen, stateDraining) {
+		return ErrPoolClosed
+	}
+	// Gets and Semaphore.Acquire waiting for an in-use slot fail at once
+	p.inUse.close()
+
+	var err error
+	if p.checkedOut() > 0 {
+		ticker := time.NewTicker(drainPollInterval)
+	wait:
+		for p.checkedOut() > 0 {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				break wait
+			case <-ticker.C:
+			}
+		}
+		ticker.Stop()
+	}
+
+	cfg := p.config()
+	for i := range p.shards {
+		for _, e := range p.takeIdle(i) {
+			p.destroy(e.obj, cfg)
+		}
+	}
+	for _, e := range p.quarantine.takeAll() {
+		p.destroy(e.obj, cfg)
+	}
+	atomic.StoreInt32(&p.state, stateClosed)
+	return err
+}
+
+// checkedOut returns the number of objects currently checked out.
+func (p *Pool) checkedOut() int64 {
+	var n int64
+	for i := range p.shards {
+		n += atomic.LoadInt64(&p.shards[i].checkedOut)
+	}
+	return n
+}
+
+// uncount ends the checkout of an object by decrementing the count of the
+// shard shardID, or of the next shard with objects checked out, and reports
+// whether any object was checked out. The counts never go below zero, so Puts
+// of objects the pool never handed out, such as the Puts pre-warming a pool,
+// do not hide objects that are checked out from Drain and InUse.
+func (p *Pool) uncount(shardID uint64) bool {
+	for i := range p.shards {
+		c := &p.shards[(shardID+uint64(i))&p.shardMask].checkedOut
+		for {
+			n := atomic.LoadInt64(c)
+			if n <= 0 {
+				break
+			}
+			if atomic.CompareAndSwapInt64(c, n, n-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// destroy releases an object that leaves the pool for good.
+func (p *Pool) destroy(obj interface{}, cfg *config) {
+	if cfg.destructor != nil {
+		cfg.destructor(obj)
+	}
+}
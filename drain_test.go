@@ -0,0 +1,75 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDrain tests that Drain waits for outstanding objects and destroys everything.
+func TestDrain(t *testing.T) {
+	var destroyed int32
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithDestructor(func(obj interface{}) {
+		atomic.AddInt32(&destroyed, 1)
+	})))
+
+	idle := p.Get()
+	out := p.Get()
+	p.Put(idle)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		p.Put(out)
+	}()
+	if err := p.Drain(context.Background()); err != nil {
+		t.Errorf("Expected Drain to succeed, got %v", err)
+	}
+
+	if n := atomic.LoadInt32(&destroyed); n != 2 {
+		t.Errorf("Expected 2 destroyed objects, got %d", n)
+	}
+	if _, err := p.GetE(); err != ErrClosed {
+		t.Errorf("Expected ErrClosed after Drain, got %v", err)
+	}
+	if err := p.Drain(context.Background()); err != ErrClosed {
+		t.Errorf("Expected a second Drain to fail, got %v", err)
+	}
+}
+
+// TestDrainTimeout tests that Drain gives up when its context is done.
+func TestDrainTimeout(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+	p.Get()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Drain(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+// TestDrainPrewarmed tests that pre-warming Puts do not hide checked out objects.
+func TestDrainPrewarmed(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	for i := 0; i < 5; i++ {
+		p.Put(new(int))
+	}
+	obj := p.Get()
+	if n := p.InUse(); n != 1 {
+		t.Errorf("Expected 1 object in use, got %d", n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Drain(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected Drain to wait for the checked out object, got %v", err)
+	}
+	p.Put(obj)
+}
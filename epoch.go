@@ -0,0 +1,29 @@
+package pool
+
+import "sync/atomic"
+
+// Invalidate marks all objects of the pool as stale, e.g. after a config
+// reload made them outdated, without blocking on the objects in use.
+// Idle objects are destroyed instead of handed out by their next Get, and
+// objects checked out at the time are destroyed when they are Put back.
+// Telling those apart from fresh ones requires WithObjectMetadata; without it
+// Invalidate fails with ErrMetadataRequired and leaves all objects valid.
+func (p *Pool) Invalidate() error {
+	if !p.config().metadata {
+		return ErrMetadataRequired
+	}
+	atomic.AddUint64(&p.epoch, 1)
+	return nil
+}
+
+// pop removes and returns an entry from shard that is not stale, destroying the
+// stale ones it finds on the way.
+func (p *Pool) pop(shard *poolShard, cfg *config) (entry, bool) {
+	for {
+		e, ok := shard.pop(cfg)
+		if !ok || e.epoch >= atomic.LoadUint64(&p.epoch) {
+			return e, ok
+		}
+		p.evicted(e, cfg)
+	}
+}
@@ -0,0 +1,66 @@
+package pool
+
+import "testing"
+
+// TestInvalidateIdle tests that idle objects are destroyed instead of handed out after Invalidate.
+func TestInvalidateIdle(t *testing.T) {
+	destroyed := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithObjectMetadata(), WithDestructor(func(obj interface{}) {
+		destroyed++
+	})))
+	old := p.Get()
+	p.Put(old)
+	if err := p.Invalidate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if obj := p.Get(); obj == old {
+		t.Error("Expected a stale object not to be handed out")
+	}
+	if destroyed != 1 {
+		t.Errorf("Expected the stale object to be destroyed, got %d", destroyed)
+	}
+	if n := idleCount(p); n != 0 {
+		t.Errorf("Expected no idle objects, got %d", n)
+	}
+}
+
+// TestInvalidateCheckedOut tests that objects checked out before Invalidate are destroyed on Put.
+func TestInvalidateCheckedOut(t *testing.T) {
+	destroyed := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithObjectMetadata(), WithDestructor(func(obj interface{}) {
+		destroyed++
+	})))
+	old := p.Get()
+	p.Invalidate()
+	fresh := p.Get()
+	p.Put(old)
+	p.Put(fresh)
+
+	if destroyed != 1 {
+		t.Errorf("Expected the stale object to be destroyed, got %d", destroyed)
+	}
+	if obj := p.Get(); obj != fresh {
+		t.Error("Expected the fresh object to be recycled")
+	}
+}
+
+// TestInvalidateNoMetadata tests that Invalidate fails and keeps the objects without metadata tracking.
+func TestInvalidateNoMetadata(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	old := p.Get()
+	p.Put(old)
+
+	if err := p.Invalidate(); err != ErrMetadataRequired {
+		t.Errorf("Expected ErrMetadataRequired, got %v", err)
+	}
+	if obj := p.Get(); obj != old {
+		t.Error("Expected the object to stay valid")
+	}
+}
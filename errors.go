@@ -0,0 +1,53 @@
+package pool
+
+import "errors"
+
+// ErrCircuitOpen is returned by GetE and GetContext when the pool is empty
+// and the factory circuit breaker is open.
+var ErrCircuitOpen = errors.New("pool: factory circuit breaker is open")
+
+// ErrExhausted is returned by GetE and GetContext when the max number of
+// objects checked out is reached and the pool does not wait.
+var ErrExhausted = errors.New("pool: too many objects in use")
+
+// ErrWeightTooLarge is matched by the error of GetWeighted when the weight
+// exceeds the in-use limit, so the Get could never succeed.
+var ErrWeightTooLarge = errors.New("pool: weight exceeds the in-use limit")
+
+// ErrUnhealthy is returned by ResourcePool.Get when no healthy object could be obtained.
+var ErrUnhealthy = errors.New("pool: no healthy object available")
+
+// ErrPoolClosed is returned by Gets on a pool that is draining or closed.
+var ErrPoolClosed = errors.New("pool: pool is closed")
+
+// ErrClosed is the former name of ErrPoolClosed.
+var ErrClosed = ErrPoolClosed
+
+// ErrTimeout is returned by GetTimeout and GetContext when the deadline passed
+// while waiting for an object to be returned to a bounded pool. The error also
+// matches context.DeadlineExceeded.
+var ErrTimeout = errors.New("pool: timed out waiting for an object")
+
+// ErrRateLimited is returned by GetE and GetContext when the pool is empty and
+// the factory rate limit set with WithNewRateLimit is reached.
+var ErrRateLimited = errors.New("pool: factory rate limit reached")
+
+// ErrNotReconfigurable is returned by Reconfigure for options that can only be
+// set when the pool is created.
+var ErrNotReconfigurable = errors.New("pool: option cannot be changed at runtime")
+
+// ErrFrozen is returned by GetE and GetContext when the pool is empty and
+// frozen, see Pool.Freeze.
+var ErrFrozen = errors.New("pool: pool is frozen")
+
+// ErrMetadataRequired is returned by Invalidate on a pool without
+// WithObjectMetadata, which cannot tell the objects checked out before it from
+// fresh ones.
+var ErrMetadataRequired = errors.New("pool: Invalidate requires WithObjectMetadata")
+
+// ErrBufferClosed is returned by the methods of a PooledBuffer after Close.
+var ErrBufferClosed = errors.New("pool: buffer is closed")
+
+// ErrInvalidConfig is matched by the errors of Validate and Pool.Validate,
+// which describe each incoherent setting.
+var ErrInvalidConfig = errors.New("pool: invalid configuration")
@@ -0,0 +1,22 @@
+package pool
+
+import "sync/atomic"
+
+// Freeze stops the pool from creating objects: until Unfreeze, Gets are only
+// served from the idle objects, and Gets that find none fail with ErrFrozen
+// (Get returns nil) while still counting as misses. This measures how much
+// traffic a pre-warmed pool carries on its own, e.g. during a load test.
+// Puts are not affected.
+func (p *Pool) Freeze() {
+	atomic.StoreInt32(&p.frozen, 1)
+}
+
+// Unfreeze lets the pool create objects again after Freeze.
+func (p *Pool) Unfreeze() {
+	atomic.StoreInt32(&p.frozen, 0)
+}
+
+// Frozen reports whether the pool is frozen.
+func (p *Pool) Frozen() bool {
+	return atomic.LoadInt32(&p.frozen) == 1
+}
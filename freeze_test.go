@@ -0,0 +1,52 @@
+package pool
+
+import "testing"
+
+// TestFreeze tests that a frozen pool only serves idle objects.
+func TestFreeze(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	obj := new(int)
+	p.Put(obj)
+	p.Freeze()
+
+	if !p.Frozen() {
+		t.Error("Expected the pool to be frozen")
+	}
+	if got := p.Get(); got != obj {
+		t.Error("Expected the idle object to be served while frozen")
+	}
+	if _, err := p.GetE(); err != ErrFrozen {
+		t.Errorf("Expected ErrFrozen on an empty frozen pool, got %v", err)
+	}
+	if s := p.Stats(); s.Misses != 1 {
+		t.Errorf("Expected the failed Get to count as a miss, got %d", s.Misses)
+	}
+
+	p.Unfreeze()
+	if p.Get() == nil {
+		t.Error("Expected Get to create objects after Unfreeze")
+	}
+}
+
+// TestFreezeRaceChaos tests that race chaos does not fail Gets of a frozen pool with idle objects.
+func TestFreezeRaceChaos(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	const n = 100
+	for i := 0; i < n; i++ {
+		p.Put(new(int))
+	}
+	p.updateConfig(func(c *config) {
+		c.raceChaos = true
+	})
+	p.Freeze()
+
+	for i := 0; i < n; i++ {
+		if _, err := p.GetE(); err != nil {
+			t.Fatalf("Expected the idle objects to be served while frozen, got %v after %d Gets", err, i)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package pool
+
+import (
+	"math"
+	"runtime"
+	"sync/atomic"
+	"weak"
+)
+
+// gcSentinel is an unreachable object whose finalizer runs once per GC cycle.
+type gcSentinel struct {
+	fn func() bool
+}
+
+// onGC calls fn after every GC cycle for as long as it returns true.
+func onGC(fn func() bool) {
+	runtime.SetFinalizer(&gcSentinel{fn: fn}, gcFinalize)
+}
+
+// gcFinalize runs the callback of s and re-arms it for the next cycle.
+func gcFinalize(s *gcSentinel) {
+	if s.fn() {
+		runtime.SetFinalizer(s, gcFinalize)
+	}
+}
+
+// startGCTrim registers p for trimming on every GC cycle.
+// The registration holds p weakly and ends once p is collected or closed.
+func (p *Pool) startGCTrim() {
+	wp := weak.Make(p)
+	onGC(func() bool {
+		p := wp.Value()
+		if p == nil || atomic.LoadInt32(&p.state) == stateClosed {
+			return false
+		}
+		cfg := p.config()
+		if cfg.gcTrimFraction > 0 {
+			p.trimFraction(cfg.gcTrimFraction, cfg)
+		}
+		return true
+	})
+}
+
+// trimFraction destroys the given fraction of the idle objects, rounded down
+// so that a fraction of less than one object trims nothing, taking from every
+// shard in proportion to its size and starting with the least recently
+// returned ones.
+func (p *Pool) trimFraction(fraction float64, cfg *config) {
+	idle := p.Len()
+	if n := int(math.Floor(float64(idle) * fraction)); n > 0 {
+		p.trimN(n, idle, cfg)
+	}
+}
+
+// trim removes and returns up to n idle entries, chosen by the eviction policy
+// of cfg if set, and the least recently returned ones otherwise.
+// s.mu must be held.
+func (s *poolShard) trim(n int, cfg *config) []entry {
+	if n <= 0 || len(s.objs) == 0 {
+		return nil
+	}
+	if n > len(s.objs) {
+		n = len(s.objs)
+	}
+	if cfg.policy != nil {
+		return s.evict(cfg.policy.SelectVictims(idleList(s.objs), n))
+	}
+	victims := make([]entry, n)
+	copy(victims, s.objs[:n])
+	rest := copy(s.objs, s.objs[n:])
+	clear(s.objs[rest:])
+	s.objs = s.objs[:rest]
+	return victims
+}
@@ -0,0 +1,66 @@
+package pool
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestGCTrim tests that a fraction of the idle objects is dropped after GC.
+func TestGCTrim(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithGCTrim(0.5)))
+	for i := 0; i < 8; i++ {
+		p.Put(new(int))
+	}
+
+	for i := 0; i < 100 && idleCount(p) == 8; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if n := idleCount(p); n == 8 || n == 0 {
+		t.Errorf("Expected part of the idle objects to be trimmed, got %d idle", n)
+	}
+}
+
+// TestTrimFraction tests that trimming removes the oldest objects first.
+func TestTrimFraction(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	objs := []*int{new(int), new(int), new(int), new(int)}
+	for _, obj := range objs {
+		p.Put(obj)
+	}
+
+	p.trimFraction(0.5, p.config())
+	if n := idleCount(p); n != 2 {
+		t.Fatalf("Expected 2 idle objects after trimming half, got %d", n)
+	}
+	if p.Get() != objs[3] || p.Get() != objs[2] {
+		t.Error("Expected the most recently returned objects to be kept")
+	}
+}
+
+// TestTrimFractionSmall tests that the fraction applies to the whole pool rather than to every shard.
+func TestTrimFractionSmall(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	for i := 0; i < 4; i++ {
+		putShard(p, uint64(i), new(int))
+	}
+
+	p.trimFraction(0.2, p.config())
+	if n := idleCount(p); n != 4 {
+		t.Errorf("Expected a fraction below one object to trim nothing, got %d idle", n)
+	}
+	for i := 0; i < 4; i++ {
+		putShard(p, uint64(i), new(int))
+	}
+	p.trimFraction(0.25, p.config())
+	if n := idleCount(p); n != 6 {
+		t.Errorf("Expected 2 of 8 idle objects to be trimmed, got %d idle", n)
+	}
+}
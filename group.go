@@ -0,0 +1,177 @@
+package pool
+
+import "sync"
+
+// Number of objects a member below its share reclaims from other members
+// before its Put is dropped
+const groupReclaimAttempts = 4
+
+// Group shares a budget of idle objects, or of idle bytes, among the pools
+// that join it with WithGroup, bounding the aggregate footprint of many pools
+// in one process.
+//
+// Members may use any budget left unused by the others. Once the budget is
+// exhausted, the budget is allocated by weighted fair share: a member below
+// budget*weight/totalWeight reclaims idle objects from the members furthest
+// above their share, destroying them, while a Put to a member at or above its
+// share is dropped. A Group keeps its members reachable; pools are expected to
+// live as long as their group.
+type Group struct {
+	budget int64
+	bytes  bool
+
+	mu          sync.Mutex
+	members     []*groupMember
+	totalWeight int64
+	used        int64
+}
+
+// groupMember is the membership of a pool in a Group.
+type groupMember struct {
+	group  *Group
+	pool   *Pool
+	weight int64
+	used   int64
+}
+
+// NewGroup creates a group sharing a budget of n idle objects.
+func NewGroup(n int64) *Group {
+	return &Group{budget: n}
+}
+
+// NewMemoryGroup creates a group sharing a budget of bytes idle bytes, as
+// reported by the members' Sizers. Objects of members without a Sizer do not
+// count.
+func NewMemoryGroup(bytes int64) *Group {
+	return &Group{budget: bytes, bytes: true}
+}
+
+// Budget returns the budget shared by the members.
+func (g *Group) Budget() int64 {
+	return g.budget
+}
+
+// Used returns the part of the budget used by the idle objects of all members.
+func (g *Group) Used() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.used
+}
+
+// Usage returns the part of the budget used by the idle objects of p, and
+// zero if p is not a member.
+func (g *Group) Usage(p *Pool) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, m := range g.members {
+		if m.pool == p {
+			return m.used
+		}
+	}
+	return 0
+}
+
+// join adds p as the pool of m to the group of m.
+func (m *groupMember) join(p *Pool) {
+	g := m.group
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	m.pool = p
+	g.members = append(g.members, m)
+	g.totalWeight += m.weight
+}
+
+// cost returns the part of the budget used by e.
+func (m *groupMember) cost(e entry) int64 {
+	if m.group.bytes {
+		return int64(e.size)
+	}
+	return 1
+}
+
+// reserve accounts e against the budget, reclaiming idle objects from members
+// above their share if the budget is exhausted and m is below its own.
+// It reports whether e may be retained.
+func (m *groupMember) reserve(e entry) bool {
+	g := m.group
+	cost := m.cost(e)
+	for i := 0; i <= groupReclaimAttempts; i++ {
+		g.mu.Lock()
+		if g.used+cost <= g.budget {
+			g.used += cost
+			m.used += cost
+			g.mu.Unlock()
+			return true
+		}
+		var victim *groupMember
+		if m.used+cost <= g.share(m) {
+			victim = g.mostOver(m)
+		}
+		g.mu.Unlock()
+		if victim == nil || !victim.pool.reclaim() {
+			return false
+		}
+	}
+	return false
+}
+
+// release returns the part of the budget used by e.
+// It is a no-op on a nil member.
+func (m *groupMember) release(e entry) {
+	if m == nil {
+		return
+	}
+	g := m.group
+	cost := m.cost(e)
+	g.mu.Lock()
+	g.used -= cost
+	m.used -= cost
+	g.mu.Unlock()
+}
+
+// share returns the fair share of the budget of m.
+// g.mu must be held.
+func (g *Group) share(m *groupMember) int64 {
+	return g.budget * m.weight / g.totalWeight
+}
+
+// mostOver returns the member other than m that is furthest above its share,
+// relative to its weight, or nil if no member is above its share.
+// g.mu must be held.
+func (g *Group) mostOver(m *groupMember) *groupMember {
+	var victim *groupMember
+	var excess float64
+	for _, o := range g.members {
+		if o == m || o.used <= g.share(o) {
+			continue
+		}
+		if x := float64(o.used-g.share(o)) / float64(o.weight); victim == nil || x > excess {
+			victim, excess = o, x
+		}
+	}
+	return victim
+}
+
+// reclaim destroys the least recently returned idle object of the first
+// non-empty shard and reports whether there was one.
+func (p *Pool) reclaim() bool {
+	cfg := p.config()
+	for i := range p.shards {
+		shard := &p.shards[i]
+		shard.mu.Lock()
+		shard.coolAll()
+		if len(shard.objs) == 0 {
+			shard.unlock()
+			continue
+		}
+		e := shard.removeAt(0)
+		overflow := shard.retier(cfg)
+		shard.unlock()
+		p.evicted(e, cfg)
+		for _, v := range overflow {
+			p.evicted(v, cfg)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package pool
+
+import "testing"
+
+// TestGroup tests that members share the budget and may use unused budget.
+func TestGroup(t *testing.T) {
+	g := NewGroup(4)
+	a := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithGroup(g, 1)))
+	b := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithGroup(g, 1)))
+
+	for i := 0; i < 4; i++ {
+		a.Put(new(int))
+	}
+	if n := g.Used(); n != 4 {
+		t.Errorf("Expected a to use the whole idle budget, got %d", n)
+	}
+	if a.PutCheck(new(int)) {
+		t.Error("Expected a Put beyond the budget and the share to be dropped")
+	}
+
+	a.Get()
+	if n := g.Usage(a); n != 3 {
+		t.Errorf("Expected Get to release budget, got %d", n)
+	}
+	a.Clear()
+	if n := g.Used(); n != 0 {
+		t.Errorf("Expected Clear to release the budget, got %d", n)
+	}
+	if n := g.Usage(b); n != 0 {
+		t.Errorf("Expected b to use nothing, got %d", n)
+	}
+}
+
+// TestGroupFairShare tests that a member below its share reclaims from the others.
+func TestGroupFairShare(t *testing.T) {
+	g := NewGroup(4)
+	destroyed := 0
+	a := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithGroup(g, 1), WithDestructor(func(obj interface{}) {
+		destroyed++
+	})))
+	b := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithGroup(g, 1)))
+	for i := 0; i < 4; i++ {
+		a.Put(new(int))
+	}
+
+	for i := 0; i < 3; i++ {
+		want := i < 2
+		if ok := b.PutCheck(new(int)); ok != want {
+			t.Errorf("Expected Put %d of b to be retained: %v, got %v", i, want, ok)
+		}
+	}
+	if ua, ub := g.Usage(a), g.Usage(b); ua != 2 || ub != 2 {
+		t.Errorf("Expected the budget to be split 2/2, got %d/%d", ua, ub)
+	}
+	if destroyed != 2 || idleCount(a) != 2 {
+		t.Errorf("Expected 2 objects of a to be reclaimed, got %d destroyed and %d idle", destroyed, idleCount(a))
+	}
+}
+
+// TestMemoryGroup tests that memory groups count the bytes reported by the Sizer.
+func TestMemoryGroup(t *testing.T) {
+	g := NewMemoryGroup(100)
+	p := predictable(NewPool(func() interface{} {
+		return make([]byte, 0, 60)
+	}, WithGroup(g, 1), WithSizer(func(obj interface{}) int {
+		return cap(obj.([]byte))
+	})))
+
+	p.Put(make([]byte, 0, 60))
+	if p.PutCheck(make([]byte, 0, 60)) {
+		t.Error("Expected the second buffer to exceed the budget")
+	}
+	if n := g.Used(); n != 60 {
+		t.Errorf("Expected 60 bytes used, got %d", n)
+	}
+}
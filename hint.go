@@ -0,0 +1,45 @@
+package pool
+
+import "context"
+
+// shardHint selects a shard by a caller-provided key instead of the stack address.
+type shardHint struct {
+	key uint64
+	set bool
+}
+
+// userHint returns the shard hint of a caller's hint. The hint is mixed first,
+// so sequential or aligned hints such as IDs or addresses spread over all
+// shards instead of selecting the same few by their low bits.
+func userHint(hint uint64) shardHint {
+	// The finalizer of MurmurHash3
+	hint ^= hint >> 33
+	hint *= 0xff51afd7ed558ccd
+	hint ^= hint >> 33
+	hint *= 0xc4ceb9fe1a85ec53
+	hint ^= hint >> 33
+	return shardHint{key: hint, set: true}
+}
+
+// hintedShard returns the shard of hint if it is set, or the shard to use under cfg.
+func (p *Pool) hintedShard(hint shardHint, cfg *config) uint64 {
+	if hint.set {
+		return hint.key & p.shardMask
+	}
+	return p.pickShard(cfg)
+}
+
+// GetHint retrieves an object from the pool like Get, starting at the shard
+// selected by hint instead of the caller's stack address. Using the same hint,
+// e.g. a connection ID, for GetHint and PutHint keeps a logical stream reusing
+// the same objects on the same shard.
+func (p *Pool) GetHint(hint uint64) interface{} {
+	obj, _ := p.getContext(context.Background(), getOptions{hint: userHint(hint)})
+	return obj
+}
+
+// PutHint returns an object to the shard selected by hint, see GetHint.
+// If the object is nil, it will be ignored.
+func (p *Pool) PutHint(hint uint64, obj interface{}) {
+	p.putHooked(obj, putOptions{hint: userHint(hint)})
+}
@@ -0,0 +1,40 @@
+package pool
+
+import "testing"
+
+// TestHint tests that objects returned with a hint are reused by Gets with the same hint.
+func TestHint(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithDeterministicSharding(1))
+	p.updateConfig(func(c *config) {
+		c.stealShardCnt = 0
+	})
+
+	a, b := new(int), new(int)
+	p.PutHint(3, a)
+	p.PutHint(4, b)
+	if obj := p.GetHint(4); obj != b {
+		t.Error("Expected the object returned with hint 4")
+	}
+	if obj := p.GetHint(3); obj != a {
+		t.Error("Expected the object returned with hint 3")
+	}
+}
+
+// TestHintSpread tests that sequential and aligned hints spread over the shards.
+func TestHintSpread(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+	cfg := p.config()
+	for _, stride := range []uint64{1, uint64(len(p.shards)), 4096} {
+		used := make(map[uint64]bool)
+		for i := uint64(0); i < 4*uint64(len(p.shards)); i++ {
+			used[p.hintedShard(userHint(i*stride), cfg)] = true
+		}
+		if len(used) < len(p.shards)/2 {
+			t.Errorf("Expected hints with a stride of %d to spread over the shards, got %d of %d", stride, len(used), len(p.shards))
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package pool
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// waitBounds are the upper bounds of the buckets of wait-time histograms.
+var waitBounds = [...]time.Duration{
+	100 * time.Microsecond, 250 * time.Microsecond, 500 * time.Microsecond,
+	time.Millisecond, 2500 * time.Microsecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// Histogram is a snapshot of a distribution of durations.
+type Histogram struct {
+	// Upper bounds of the buckets, in increasing order
+	Bounds []time.Duration `json:"bounds_ns"`
+	// Number of durations per bucket: Counts[i] counts the durations up to
+	// Bounds[i], the last one the durations above every bound
+	Counts []uint64 `json:"counts"`
+	// Number of recorded durations
+	Count uint64 `json:"count"`
+	// Sum of the recorded durations
+	Sum time.Duration `json:"sum_ns"`
+}
+
+// Mean returns the mean of the recorded durations, or 0 if there are none.
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile returns an upper bound of the q-quantile (0 <= q <= 1) of the
+// recorded durations: the bound of the bucket holding it. Durations above every
+// bound count as the largest bound. It returns 0 if there are none.
+func (h Histogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 || len(h.Bounds) == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.Count)))
+	if rank == 0 {
+		rank = 1
+	}
+	var seen uint64
+	for i, n := range h.Counts {
+		seen += n
+		if seen >= rank && i < len(h.Bounds) {
+			return h.Bounds[i]
+		}
+	}
+	return h.Bounds[len(h.Bounds)-1]
+}
+
+// delta returns the durations recorded since prev, an earlier snapshot of the
+// same histogram.
+func (h Histogram) delta(prev Histogram) Histogram {
+	d := h
+	d.Counts = append([]uint64(nil), h.Counts...)
+	for i := range d.Counts {
+		if i < len(prev.Counts) {
+			d.Counts[i] -= prev.Counts[i]
+		}
+	}
+	d.Count -= prev.Count
+	d.Sum -= prev.Sum
+	return d
+}
+
+// histogram records durations into the waitBounds buckets without locking.
+type histogram struct {
+	counts [len(waitBounds) + 1]uint64
+	sum    int64
+}
+
+// record adds d to the histogram.
+func (h *histogram) record(d time.Duration) {
+	i := sort.Search(len(waitBounds), func(i int) bool { return d <= waitBounds[i] })
+	atomic.AddUint64(&h.counts[i], 1)
+	atomic.AddInt64(&h.sum, int64(d))
+}
+
+// snapshot returns the current state of the histogram.
+func (h *histogram) snapshot() Histogram {
+	s := Histogram{
+		Bounds: append([]time.Duration(nil), waitBounds[:]...),
+		Counts: make([]uint64, len(h.counts)),
+		Sum:    time.Duration(atomic.LoadInt64(&h.sum)),
+	}
+	for i := range h.counts {
+		s.Counts[i] = atomic.LoadUint64(&h.counts[i])
+		s.Count += s.Counts[i]
+	}
+	return s
+}
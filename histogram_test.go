@@ -0,0 +1,69 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// TestHistogram tests recording durations and reading quantiles.
+func TestHistogram(t *testing.T) {
+	var h histogram
+	for i := 0; i < 9; i++ {
+		h.record(50 * time.Microsecond)
+	}
+	h.record(time.Minute)
+
+	s := h.snapshot()
+	if s.Count != 10 || s.Counts[0] != 9 || s.Counts[len(s.Counts)-1] != 1 {
+		t.Errorf("Expected 9 short and 1 long duration, got %v", s.Counts)
+	}
+	if q := s.Quantile(0.5); q != 100*time.Microsecond {
+		t.Errorf("Expected median bound 100µs, got %v", q)
+	}
+	if q := s.Quantile(1); q != 10*time.Second {
+		t.Errorf("Expected max to be clamped to 10s, got %v", q)
+	}
+	if m := s.Mean(); m != (9*50*time.Microsecond+time.Minute)/10 {
+		t.Errorf("Unexpected mean %v", m)
+	}
+	if (Histogram{}).Quantile(0.5) != 0 || (Histogram{}).Mean() != 0 {
+		t.Error("Expected an empty histogram to report 0")
+	}
+}
+
+// TestHistogramOddCount tests that quantiles of odd counts round the rank up.
+func TestHistogramOddCount(t *testing.T) {
+	var h histogram
+	h.record(50 * time.Microsecond)
+	h.record(time.Second)
+	h.record(time.Second)
+
+	s := h.snapshot()
+	if q := s.Quantile(0.5); q != time.Second {
+		t.Errorf("Expected the median of 1 low and 2 high durations to be high, got %v", q)
+	}
+	if q := s.Quantile(0); q != 100*time.Microsecond {
+		t.Errorf("Expected the min to be the low bucket, got %v", q)
+	}
+}
+
+// TestWaitHistogram tests that Gets of a waiting pool are recorded.
+func TestWaitHistogram(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithMaxInUse(1, true))
+	obj := p.Get()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.Put(obj)
+	}()
+	p.Get()
+
+	w := p.Stats().Waits
+	if w.Count != 2 || w.Counts[0] != 1 {
+		t.Errorf("Expected 2 recorded Gets, one without waiting, got %v", w.Counts)
+	}
+	if q := w.Quantile(1); q < 10*time.Millisecond {
+		t.Errorf("Expected the slowest Get to have waited at least 10ms, got %v", q)
+	}
+}
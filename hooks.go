@@ -0,0 +1,22 @@
+package pool
+
+// Hooks are functions called on pool lifecycle events, e.g. for logging,
+// tracing or custom accounting. Any of them may be nil; unset hooks cost a nil
+// check. Hooks run synchronously on the goroutine causing the event, so they
+// should be fast and must not call back into the pool.
+type Hooks struct {
+	// OnGet is called with every object handed out by Get
+	OnGet func(obj interface{})
+	// OnPut is called with every object returned by Put, before it is stored
+	OnPut func(obj interface{})
+	// OnMiss is called when a Get finds no idle object and a new one is created
+	OnMiss func()
+	// OnSteal is called with an idle object a Get took from shard, which is not
+	// the shard it preferred
+	OnSteal func(obj interface{}, shard int)
+	// OnDiscard is called with objects passed to Discard and with returned
+	// objects that the pool did not retain
+	OnDiscard func(obj interface{})
+	// OnEvict is called with idle objects destroyed to make room or to trim the pool
+	OnEvict func(obj interface{})
+}
@@ -0,0 +1,58 @@
+package pool
+
+import "testing"
+
+// TestHooks tests that hooks are called on lifecycle events.
+func TestHooks(t *testing.T) {
+	counts := map[string]int{}
+	stolenFrom := -1
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithHooks(Hooks{
+		OnGet:     func(obj interface{}) { counts["get"]++ },
+		OnPut:     func(obj interface{}) { counts["put"]++ },
+		OnMiss:    func() { counts["miss"]++ },
+		OnSteal:   func(obj interface{}, shard int) { counts["steal"]++; stolenFrom = shard },
+		OnDiscard: func(obj interface{}) { counts["discard"]++ },
+		OnEvict:   func(obj interface{}) { counts["evict"]++ },
+	})))
+
+	p.Discard(p.Get())
+
+	other := (busiestShard(p.Stats()) + 1) & p.shardMask
+	p.shards[other].push(entry{obj: new(int)}, shardCap, nil)
+	p.Get()
+
+	p.Put(new(int))
+	p.Put(new(int))
+	p.Trim(0)
+	p.Put(nil)
+
+	want := map[string]int{"get": 2, "put": 2, "miss": 1, "steal": 1, "discard": 1, "evict": 2}
+	for k, v := range want {
+		if counts[k] != v {
+			t.Errorf("Expected %d %s hooks, got %d", v, k, counts[k])
+		}
+	}
+	if stolenFrom != int(other) {
+		t.Errorf("Expected steal from shard %d, got %d", other, stolenFrom)
+	}
+}
+
+// TestHooksDiscardOnDrop tests that OnDiscard is called for Puts the pool does not retain.
+func TestHooksDiscardOnDrop(t *testing.T) {
+	discarded := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithHooks(Hooks{
+		OnDiscard: func(obj interface{}) { discarded++ },
+	})))
+	p.updateConfig(func(c *config) {
+		c.shardCap = 0
+	})
+
+	p.Put(p.Get())
+	if discarded != 1 {
+		t.Errorf("Expected 1 discard, got %d", discarded)
+	}
+}
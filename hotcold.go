@@ -0,0 +1,125 @@
+package pool
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// pushHotLocked adds an entry to the hot tier of the shard. If the tier is
+// full, its oldest entry is demoted to the cold tier to make room, evicting or
+// dropping cold entries as push does. It reports whether the entry was added
+// and returns the entries that left the shard.
+// s.mu must be held.
+func (s *poolShard) pushHotLocked(e entry, cfg *config) (bool, []entry) {
+	var evicted []entry
+	if len(s.hot) >= cfg.hotCap {
+		evicted = s.demote(1, cfg)
+	}
+	s.hot = append(s.hot, e)
+	return true, evicted
+}
+
+// demote moves the n oldest hot entries to the cold tier and returns the
+// entries that did not fit.
+// s.mu must be held.
+func (s *poolShard) demote(n int, cfg *config) []entry {
+	n = min(n, len(s.hot))
+	var evicted []entry
+	for _, e := range s.hot[:n] {
+		ok, v := s.pushLocked(e, s.limit(cfg), pushPolicy(cfg))
+		evicted = append(evicted, v...)
+		if !ok && cfg.softCap > 0 {
+			ok = s.pushSoftLocked(e, cfg)
+		}
+		if !ok {
+			evicted = append(evicted, e)
+		}
+	}
+	rest := copy(s.hot, s.hot[n:])
+	clear(s.hot[rest:])
+	s.hot = s.hot[:rest]
+	return evicted
+}
+
+// coolAll moves every hot and soft entry to the tail of the cold tier,
+// regardless of its capacity, so whole-shard operations only have to look at
+// s.objs. The entries keep their tier timestamps, and operations that leave
+// entries in the shard call retier afterwards.
+// s.mu must be held.
+func (s *poolShard) coolAll() {
+	if len(s.soft) > 0 {
+		s.objs = append(s.objs, s.soft...)
+		clear(s.soft)
+		s.soft = s.soft[:0]
+	}
+	if len(s.hot) == 0 {
+		return
+	}
+	s.objs = append(s.objs, s.hot...)
+	clear(s.hot)
+	s.hot = s.hot[:0]
+}
+
+// startHotCold starts demoting hot objects older than age to the cold tier.
+func (p *Pool) startHotCold(age time.Duration) {
+	if age <= 0 {
+		age = time.Second
+	}
+	p.runTask(max(age/2, time.Millisecond), func(p *Pool) {
+		p.cool(p.config().clock.Now().Add(-age))
+	})
+}
+
+// cool demotes the hot objects that entered their tier before cutoff.
+// Hot entries are ordered by age, so each shard demotes a prefix of its tier.
+func (p *Pool) cool(cutoff time.Time) {
+	cfg := p.config()
+	for i := range p.shards {
+		shard := &p.shards[i]
+		shard.mu.Lock()
+		n := 0
+		for n < len(shard.hot) && shard.hot[n].heated < cutoff.UnixNano() {
+			n++
+		}
+		evicted := shard.demote(n, cfg)
+		shard.unlock()
+		for _, e := range evicted {
+			p.evicted(e, cfg)
+		}
+	}
+}
+
+// retier moves the cold entries above the shard's capacity, which coolAll
+// merged from the other tiers, back to the hot tier if they came from it and
+// to the soft tier otherwise, and returns those that fit neither. Soft entries
+// keep their expiry and the others get a fresh one.
+// s.mu must be held.
+func (s *poolShard) retier(cfg *config) []entry {
+	limit := max(s.limit(cfg), 0)
+	if len(s.objs) <= limit {
+		return nil
+	}
+	excess := slices.Clone(s.objs[limit:])
+	clear(s.objs[limit:])
+	s.objs = s.objs[:limit]
+	var evicted []entry
+	soft := len(s.soft)
+	for _, e := range excess {
+		switch {
+		case cfg.hotCap > 0 && e.heated != 0 && len(s.hot) < cfg.hotCap:
+			s.hot = append(s.hot, e)
+		case e.expires != 0 && len(s.soft) < cfg.softCap:
+			s.soft = append(s.soft, e)
+		case !s.pushSoftLocked(e, cfg):
+			evicted = append(evicted, e)
+		}
+	}
+	if len(s.soft) > soft {
+		// Decay takes expired entries from the front
+		slices.SortStableFunc(s.soft, func(a, b entry) int {
+			return cmp.Compare(a.expires, b.expires)
+		})
+	}
+	return evicted
+}
@@ -0,0 +1,108 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// tierCounts returns the number of hot and cold idle objects of p.
+func tierCounts(p *Pool) (hot, cold int) {
+	for i := range p.shards {
+		shard := &p.shards[i]
+		shard.mu.Lock()
+		hot += len(shard.hot)
+		cold += len(shard.objs)
+		shard.mu.Unlock()
+	}
+	return hot, cold
+}
+
+// TestHotCold tests that Gets prefer hot objects and full hot tiers demote to cold.
+func TestHotCold(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithFIFO(), WithHotCold(2, time.Hour)))
+	a, b, c := new(int), new(int), new(int)
+	p.Put(a)
+	p.Put(b)
+	p.Put(c)
+
+	if hot, cold := tierCounts(p); hot != 2 || cold != 1 {
+		t.Errorf("Expected 2 hot and 1 cold objects, got %d and %d", hot, cold)
+	}
+	if obj := p.Get(); obj != c {
+		t.Error("Expected the newest hot object to be reused first")
+	}
+	if obj := p.Get(); obj != b {
+		t.Error("Expected the remaining hot object to be reused next")
+	}
+	if obj := p.Get(); obj != a {
+		t.Error("Expected the cold object to be reused last")
+	}
+}
+
+// TestHotColdAge tests that cool demotes hot objects older than the cutoff.
+func TestHotColdAge(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithHotCold(4, time.Hour)))
+	for i := 0; i < 3; i++ {
+		p.Put(new(int))
+	}
+
+	p.cool(time.Now().Add(-time.Minute))
+	if hot, cold := tierCounts(p); hot != 3 || cold != 0 {
+		t.Errorf("Expected recent objects to stay hot, got %d hot and %d cold", hot, cold)
+	}
+	p.cool(time.Now().Add(time.Minute))
+	if hot, cold := tierCounts(p); hot != 0 || cold != 3 {
+		t.Errorf("Expected old objects to be demoted, got %d hot and %d cold", hot, cold)
+	}
+}
+
+// TestHotColdCapacity tests that demoted objects respect the cold capacity.
+func TestHotColdCapacity(t *testing.T) {
+	evicted := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithHotCold(1, time.Hour), WithHooks(Hooks{
+		OnEvict: func(obj interface{}) {
+			evicted++
+		},
+	})))
+	p.updateConfig(func(c *config) {
+		c.shardCap = 1
+	})
+	for i := 0; i < 3; i++ {
+		p.Put(new(int))
+	}
+
+	if n := idleCount(p); n != 2 {
+		t.Errorf("Expected 2 idle objects, got %d", n)
+	}
+	if evicted != 1 {
+		t.Errorf("Expected 1 demoted object to be evicted, got %d", evicted)
+	}
+	if n := p.Trim(0); n != 2 {
+		t.Errorf("Expected Trim to remove both tiers, got %d", n)
+	}
+}
+
+// TestHotColdWholeShard tests that whole-shard operations give hot objects their tier back.
+func TestHotColdWholeShard(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithShardCap(2), WithHotCold(2, time.Hour)))
+	for i := 0; i < 4; i++ {
+		putShard(p, 0, new(int))
+	}
+
+	p.ClearWhere(func(obj interface{}) bool { return false })
+	shard := &p.shards[0]
+	shard.mu.Lock()
+	cold, hot := len(shard.objs), len(shard.hot)
+	shard.mu.Unlock()
+	if cold != 2 || hot != 2 {
+		t.Errorf("Expected 2 cold and 2 hot objects, got %d and %d", cold, hot)
+	}
+}
@@ -0,0 +1,285 @@
+// Package httputilpool provides pools of the objects net/http servers
+// allocate per request, readers, response buffers and gzip writers, along
+// with middleware built on them.
+//
+// The pools accept the options of package pool, so they can be bounded with
+// pool.WithMaxInUse. Gets then fail with pool.ErrExhausted or pool.ErrTimeout
+// instead of allocating, and the middleware answers 503 Service Unavailable,
+// which turns the pool limit into admission control.
+package httputilpool
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ongniud/pool"
+)
+
+// Default max capacity of the buffers retained by a BufferPool (64KB)
+const DefaultMaxBufferSize = 64 << 10
+
+// ReaderPool is a pool of *bytes.Reader, e.g. to serve request bodies or
+// cached responses from byte slices.
+type ReaderPool struct {
+	pool *pool.Pool
+}
+
+// NewReaderPool creates a new reader pool.
+func NewReaderPool(opts ...pool.Option) *ReaderPool {
+	return &ReaderPool{pool: pool.NewPool(func() interface{} {
+		return bytes.NewReader(nil)
+	}, opts...)}
+}
+
+// Get retrieves a reader reading from b.
+func (rp *ReaderPool) Get(b []byte) (*bytes.Reader, error) {
+	obj, err := rp.pool.GetE()
+	if err != nil {
+		return nil, err
+	}
+	r := obj.(*bytes.Reader)
+	r.Reset(b)
+	return r, nil
+}
+
+// Put returns a reader to the pool, dropping its reference to the slice it read.
+func (rp *ReaderPool) Put(r *bytes.Reader) {
+	if r == nil {
+		return
+	}
+	r.Reset(nil)
+	rp.pool.Put(r)
+}
+
+// BufferPool is a pool of response buffers.
+// Buffers that grew above the max size are discarded on Put rather than
+// retained, so a few large responses do not pin memory for good.
+type BufferPool struct {
+	pool    *pool.Pool
+	maxSize int
+}
+
+// NewBufferPool creates a new buffer pool retaining buffers of up to maxSize
+// bytes of capacity. If maxSize <= 0 a default of 64KB is used.
+func NewBufferPool(maxSize int, opts ...pool.Option) *BufferPool {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBufferSize
+	}
+	return &BufferPool{maxSize: maxSize, pool: pool.NewPool(func() interface{} {
+		return new(bytes.Buffer)
+	}, opts...)}
+}
+
+// Get retrieves an empty buffer.
+func (bp *BufferPool) Get() (*bytes.Buffer, error) {
+	obj, err := bp.pool.GetE()
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*bytes.Buffer), nil
+}
+
+// Put resets a buffer and returns it to the pool, or discards it if its
+// capacity exceeds the max size.
+func (bp *BufferPool) Put(buf *bytes.Buffer) {
+	if buf == nil {
+		return
+	}
+	if buf.Cap() > bp.maxSize {
+		bp.pool.Discard(buf)
+		return
+	}
+	buf.Reset()
+	bp.pool.Put(buf)
+}
+
+// GzipWriterPool is a pool of gzip writers of a compression level.
+type GzipWriterPool struct {
+	pool *pool.Pool
+}
+
+// NewGzipWriterPool creates a new pool of gzip writers compressing at level,
+// one of the levels accepted by gzip.NewWriterLevel.
+func NewGzipWriterPool(level int, opts ...pool.Option) (*GzipWriterPool, error) {
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		return nil, err
+	}
+	return &GzipWriterPool{pool: pool.NewPool(func() interface{} {
+		zw, _ := gzip.NewWriterLevel(io.Discard, level)
+		return zw
+	}, opts...)}, nil
+}
+
+// Get retrieves a gzip writer writing to w.
+func (zp *GzipWriterPool) Get(w io.Writer) (*gzip.Writer, error) {
+	obj, err := zp.pool.GetE()
+	if err != nil {
+		return nil, err
+	}
+	zw := obj.(*gzip.Writer)
+	zw.Reset(w)
+	return zw, nil
+}
+
+// Put returns a gzip writer to the pool, dropping its reference to the
+// writer it wrote to. Callers close the writer first to flush its output.
+func (zp *GzipWriterPool) Put(zw *gzip.Writer) {
+	if zw == nil {
+		return
+	}
+	zw.Reset(io.Discard)
+	zp.pool.Put(zw)
+}
+
+// unavailable answers a request that could not get a pooled object.
+func unavailable(w http.ResponseWriter) {
+	http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
+
+// Buffered returns a handler that runs h with its response body buffered in
+// a buffer of bp, then sends it with a Content-Length header. If no buffer is
+// available, it answers 503 Service Unavailable without running h.
+func Buffered(h http.Handler, bp *BufferPool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, err := bp.Get()
+		if err != nil {
+			unavailable(w)
+			return
+		}
+		defer bp.Put(buf)
+		bw := &bufferedWriter{ResponseWriter: w, buf: buf, status: http.StatusOK}
+		h.ServeHTTP(bw, r)
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		w.WriteHeader(bw.status)
+		w.Write(buf.Bytes())
+	})
+}
+
+// bufferedWriter is a response writer holding the body and status back.
+type bufferedWriter struct {
+	http.ResponseWriter
+	buf    *bytes.Buffer
+	status int
+}
+
+func (bw *bufferedWriter) WriteHeader(status int) { bw.status = status }
+
+func (bw *bufferedWriter) Write(b []byte) (int, error) { return bw.buf.Write(b) }
+
+// Gzip returns a handler that compresses the responses of h with a writer of
+// zp for requests accepting gzip. Responses without a body, to HEAD requests
+// or with a Content-Encoding set by h are sent unchanged. If no writer is
+// available, it answers 503 Service Unavailable without running h.
+func Gzip(h http.Handler, zp *GzipWriterPool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		zw, err := zp.Get(w)
+		if err != nil {
+			unavailable(w)
+			return
+		}
+		defer zp.Put(zw)
+		gw := &gzipWriter{ResponseWriter: w, zw: zw}
+		h.ServeHTTP(gw, r)
+		if gw.compress {
+			zw.Close()
+		}
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header value accepts gzip
+// with a q-value above zero, explicitly or through "*".
+func acceptsGzip(accept string) bool {
+	gzipQ, anyQ := -1.0, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(param, "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				v = 0
+			}
+			q = v
+		}
+		switch name = strings.TrimSpace(name); {
+		case strings.EqualFold(name, "gzip"):
+			gzipQ = q
+		case name == "*":
+			anyQ = q
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return anyQ > 0
+}
+
+// gzipWriter is a response writer compressing the body. It decides whether to
+// compress once the header is written.
+type gzipWriter struct {
+	http.ResponseWriter
+	zw          *gzip.Writer
+	wroteHeader bool
+	compress    bool
+}
+
+func (gw *gzipWriter) WriteHeader(status int) {
+	if gw.wroteHeader {
+		return
+	}
+	if status >= 200 {
+		gw.wroteHeader = true
+		h := gw.Header()
+		gw.compress = status != http.StatusNoContent && status != http.StatusNotModified &&
+			h.Get("Content-Encoding") == ""
+		if gw.compress {
+			h.Set("Content-Encoding", "gzip")
+			h.Del("Content-Length")
+		}
+	}
+	gw.ResponseWriter.WriteHeader(status)
+}
+
+func (gw *gzipWriter) Write(b []byte) (int, error) {
+	if !gw.wroteHeader {
+		if h := gw.Header(); h.Get("Content-Type") == "" {
+			// Sniff the plain body, not the compressed one
+			h.Set("Content-Type", http.DetectContentType(b))
+		}
+		gw.WriteHeader(http.StatusOK)
+	}
+	if !gw.compress {
+		return gw.ResponseWriter.Write(b)
+	}
+	return gw.zw.Write(b)
+}
+
+// Flush sends the data compressed so far to the client, for streaming handlers.
+func (gw *gzipWriter) Flush() {
+	if !gw.wroteHeader {
+		gw.WriteHeader(http.StatusOK)
+	}
+	if gw.compress {
+		gw.zw.Flush()
+	}
+	if f, ok := gw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer, for http.ResponseController.
+func (gw *gzipWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
@@ -0,0 +1,197 @@
+package httputilpool
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ongniud/pool"
+)
+
+// hello is a handler writing a fixed body.
+var hello = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusCreated)
+	io.WriteString(w, strings.Repeat("hello ", 100))
+})
+
+// TestReaderPool tests that readers read the given slice and drop it on Put.
+func TestReaderPool(t *testing.T) {
+	rp := NewReaderPool()
+	r, err := rp.Get([]byte("body"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if b, _ := io.ReadAll(r); string(b) != "body" {
+		t.Errorf("Expected to read the slice, got %q", b)
+	}
+	rp.Put(r)
+	if r.Size() != 0 {
+		t.Error("Expected Put to drop the slice")
+	}
+}
+
+// TestBufferPool tests that oversized buffers are not retained.
+func TestBufferPool(t *testing.T) {
+	bp := NewBufferPool(1024, pool.WithMaxInUse(1, false))
+	buf, _ := bp.Get()
+	buf.Grow(4096)
+	bp.Put(buf)
+
+	buf, err := bp.Get()
+	if err != nil {
+		t.Fatalf("Expected the discarded buffer to free its slot, got %v", err)
+	}
+	if buf.Cap() >= 4096 {
+		t.Error("Expected the oversized buffer not to be reused")
+	}
+	if _, err := bp.Get(); err != pool.ErrExhausted {
+		t.Errorf("Expected ErrExhausted, got %v", err)
+	}
+}
+
+// TestBuffered tests that buffered responses are sent with their length.
+func TestBuffered(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Buffered(hello, NewBufferPool(0)).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("Expected the status of the handler, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Length"); got != "600" {
+		t.Errorf("Expected Content-Length 600, got %q", got)
+	}
+}
+
+// TestGzip tests that responses are compressed for clients accepting gzip.
+func TestGzip(t *testing.T) {
+	zp, err := NewGzipWriterPool(gzip.BestSpeed)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	h := Gzip(hello, zp)
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatal("Expected a gzip response")
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("Expected a valid gzip stream, got %v", err)
+		}
+		if b, _ := io.ReadAll(zr); len(b) != 600 {
+			t.Errorf("Expected 600 bytes after decompression, got %d", len(b))
+		}
+	}
+	if _, err := NewGzipWriterPool(42); err == nil {
+		t.Error("Expected an error for an invalid level")
+	}
+}
+
+// TestGzipExhausted tests that the middleware sheds load when the pool is exhausted.
+func TestGzipExhausted(t *testing.T) {
+	zp, _ := NewGzipWriterPool(gzip.DefaultCompression, pool.WithMaxInUse(1, false))
+	zw, _ := zp.Get(io.Discard)
+	defer zp.Put(zw)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	Gzip(hello, zp).ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rec.Code)
+	}
+}
+
+// TestGzipUncompressed tests that bodyless, HEAD and already encoded responses are sent unchanged.
+func TestGzipUncompressed(t *testing.T) {
+	zp, _ := NewGzipWriterPool(gzip.BestSpeed)
+	status := func(code int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+		})
+	}
+	encoded := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		io.WriteString(w, "brotli")
+	})
+	cases := []struct {
+		method string
+		h      http.Handler
+		body   string
+	}{
+		{"GET", status(http.StatusNoContent), ""},
+		{"GET", status(http.StatusNotModified), ""},
+		{"HEAD", hello, strings.Repeat("hello ", 100)},
+		{"GET", encoded, "brotli"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		Gzip(c.h, zp).ServeHTTP(rec, req)
+
+		if enc := rec.Header().Get("Content-Encoding"); enc == "gzip" {
+			t.Errorf("Expected no gzip encoding for %s %d, got %q", c.method, rec.Code, enc)
+		}
+		if body := rec.Body.String(); body != c.body {
+			t.Errorf("Expected the body %q for %s %d, got %q", c.body, c.method, rec.Code, body)
+		}
+	}
+}
+
+// TestAcceptsGzip tests the parsing of Accept-Encoding headers.
+func TestAcceptsGzip(t *testing.T) {
+	cases := map[string]bool{
+		"gzip":                true,
+		"deflate, GZIP;q=0.5": true,
+		"gzip;q=0":            false,
+		"gzip; q=0.0, *":      false,
+		"*":                   true,
+		"*;q=0":               false,
+		"deflate":             false,
+		"":                    false,
+		"x-gzipped":           false,
+	}
+	for header, want := range cases {
+		if got := acceptsGzip(header); got != want {
+			t.Errorf("Expected %v for %q, got %v", want, header, got)
+		}
+	}
+}
+
+// TestGzipFlush tests that streamed chunks reach the client compressed as they are flushed.
+func TestGzipFlush(t *testing.T) {
+	zp, _ := NewGzipWriterPool(gzip.BestSpeed)
+	var flushed int
+	h := Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "chunk")
+		w.(http.Flusher).Flush()
+		flushed = w.(*gzipWriter).ResponseWriter.(*httptest.ResponseRecorder).Body.Len()
+	}), zp)
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if flushed == 0 || !rec.Flushed {
+		t.Error("Expected the chunk to be flushed to the client")
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("Expected a valid gzip stream, got %v", err)
+	}
+	if b, _ := io.ReadAll(zr); string(b) != "chunk" {
+		t.Errorf("Expected the streamed body, got %q", b)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Expected the content type of the plain body, got %q", ct)
+	}
+}
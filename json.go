@@ -0,0 +1,77 @@
+package pool
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// JSONEncoderPool is a pool of json.Encoders, which cannot be reset to a new
+// writer and are re-bound through a Proxy instead. Encoders that failed to
+// write are discarded, since they keep returning their error.
+type JSONEncoderPool struct {
+	*BindPool[*json.Encoder]
+}
+
+// NewJSONEncoderPool creates a new encoder pool. configure, if not nil, is
+// called with every new encoder, e.g. to call SetIndent or SetEscapeHTML.
+func NewJSONEncoderPool(configure func(enc *json.Encoder), opts ...Option) *JSONEncoderPool {
+	return &JSONEncoderPool{NewBindPool(func(p *Proxy) *json.Encoder {
+		enc := json.NewEncoder(p)
+		if configure != nil {
+			configure(enc)
+		}
+		return enc
+	}, nil, opts...)}
+}
+
+// Encode writes the JSON encoding of v to w with a pooled encoder.
+func (ep *JSONEncoderPool) Encode(w io.Writer, v interface{}) error {
+	b, err := ep.Get(nil, w)
+	if err != nil {
+		return err
+	}
+	err = b.Value.Encode(v)
+	b.Fail(err)
+	ep.Put(b)
+	return err
+}
+
+// JSONDecoderPool is a pool of json.Decoders, which cannot be reset to a new
+// reader and are re-bound through a Proxy instead. Decoders that failed, or
+// that buffered input beyond the decoded values, are discarded, so no input
+// of one reader is ever decoded from another.
+type JSONDecoderPool struct {
+	*BindPool[*json.Decoder]
+}
+
+// NewJSONDecoderPool creates a new decoder pool. configure, if not nil, is
+// called with every new decoder, e.g. to call UseNumber or
+// DisallowUnknownFields.
+func NewJSONDecoderPool(configure func(dec *json.Decoder), opts ...Option) *JSONDecoderPool {
+	return &JSONDecoderPool{NewBindPool(func(p *Proxy) *json.Decoder {
+		dec := json.NewDecoder(p)
+		if configure != nil {
+			configure(dec)
+		}
+		return dec
+	}, drained, opts...)}
+}
+
+// drained reports whether dec holds no buffered input but white space.
+func drained(dec *json.Decoder) bool {
+	rest, _ := io.ReadAll(dec.Buffered())
+	return len(bytes.TrimSpace(rest)) == 0
+}
+
+// Decode decodes the JSON value read from r into v with a pooled decoder.
+func (dp *JSONDecoderPool) Decode(r io.Reader, v interface{}) error {
+	b, err := dp.Get(r, nil)
+	if err != nil {
+		return err
+	}
+	err = b.Value.Decode(v)
+	b.Fail(err)
+	dp.Put(b)
+	return err
+}
@@ -0,0 +1,48 @@
+package pool
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestJSONEncoderPool tests that pooled encoders keep their configuration.
+func TestJSONEncoderPool(t *testing.T) {
+	ep := NewJSONEncoderPool(func(enc *json.Encoder) {
+		enc.SetEscapeHTML(false)
+	})
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		if err := ep.Encode(&buf, map[string]string{"a": "<b>"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := buf.String(); got != "{\"a\":\"<b>\"}\n" {
+			t.Errorf("Expected unescaped HTML, got %q", got)
+		}
+	}
+}
+
+// TestJSONDecoderPool tests that decoders with leftover input are not reused.
+func TestJSONDecoderPool(t *testing.T) {
+	dp := NewJSONDecoderPool(nil)
+	predictable(dp.pool)
+
+	var v struct{ A int }
+	if err := dp.Decode(strings.NewReader(`{"A": 1}`), &v); err != nil || v.A != 1 {
+		t.Fatalf("Expected A=1, got %d and %v", v.A, err)
+	}
+	if n := idleCount(dp.pool); n != 1 {
+		t.Errorf("Expected the drained decoder to be retained, got %d idle", n)
+	}
+
+	if err := dp.Decode(strings.NewReader(`{"A": 2} {"A": 3}`), &v); err != nil || v.A != 2 {
+		t.Fatalf("Expected A=2, got %d and %v", v.A, err)
+	}
+	if n := idleCount(dp.pool); n != 0 {
+		t.Errorf("Expected the decoder with leftover input to be discarded, got %d idle", n)
+	}
+	if err := dp.Decode(strings.NewReader(`{"A": 4}`), &v); err != nil || v.A != 4 {
+		t.Errorf("Expected A=4 from a fresh reader, got %d and %v", v.A, err)
+	}
+}
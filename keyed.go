@@ -0,0 +1,174 @@
+package pool
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// KeyedPool is a pool of pools indexed by key.
+// A sub-pool is lazily created for each key on first use. Sub-pools that have
+// not been used for longer than the idle timeout are evicted together with
+// their idle objects.
+//
+// Concurrent misses for the same key are collapsed, so a burst on a cold key
+// does not run an expensive construction N times at once: a Get that finds the
+// sub-pool empty while a factory call for the key runs waits for that call and
+// then tries the sub-pool again, and at most one factory call per key runs at a
+// time. Every caller still receives an object of its own.
+type KeyedPool struct {
+	newFunc     func(key string) interface{}
+	idleTimeout time.Duration
+	opts        []Option
+	lastSweep   int64
+
+	mu    sync.RWMutex
+	pools map[string]*keyedSubPool
+}
+
+// keyedSubPool is the sub-pool of a single key.
+type keyedSubPool struct {
+	*Pool
+	lastUsed int64
+	// Factory call in flight for the key, nil if none
+	flight atomic.Pointer[keyedFlight]
+}
+
+// keyedFlight is a factory call in flight for a key.
+type keyedFlight struct {
+	// Closed once the call returned
+	done chan struct{}
+}
+
+// NewKeyedPool creates a new keyed pool.
+// fn is the function used to create a new object for a key when its sub-pool is empty.
+// Sub-pools idle for longer than idleTimeout are evicted; if idleTimeout <= 0 they are never evicted.
+// opts are applied to every sub-pool.
+func NewKeyedPool(fn func(key string) interface{}, idleTimeout time.Duration, opts ...Option) *KeyedPool {
+	if fn == nil {
+		panic("newFunc cannot be nil")
+	}
+	return &KeyedPool{
+		newFunc:     fn,
+		idleTimeout: idleTimeout,
+		opts:        opts,
+		lastSweep:   time.Now().UnixNano(),
+		pools:       make(map[string]*keyedSubPool),
+	}
+}
+
+// Get retrieves an object from the sub-pool of key.
+func (kp *KeyedPool) Get(key string) interface{} {
+	now := time.Now().UnixNano()
+	kp.maybeEvict(now)
+	sp := kp.subPool(key)
+	atomic.StoreInt64(&sp.lastUsed, now)
+	if f := sp.flight.Load(); f != nil && sp.Len() == 0 {
+		// The object of the call in flight may be returned before it ends
+		<-f.done
+	}
+	return sp.Get()
+}
+
+// Put returns an object to the sub-pool of key.
+// If the object is nil, it will be ignored.
+func (kp *KeyedPool) Put(key string, obj interface{}) {
+	if obj == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	sp := kp.subPool(key)
+	atomic.StoreInt64(&sp.lastUsed, now)
+	sp.Put(obj)
+}
+
+// KeyCount returns the number of keys that currently have a sub-pool.
+func (kp *KeyedPool) KeyCount() int {
+	kp.mu.RLock()
+	defer kp.mu.RUnlock()
+	return len(kp.pools)
+}
+
+// Evict removes the sub-pools that have been idle for longer than the idle timeout
+// and returns the number of removed keys.
+func (kp *KeyedPool) Evict() int {
+	return kp.evict(time.Now().UnixNano())
+}
+
+// Clear removes all sub-pools.
+func (kp *KeyedPool) Clear() {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	for key, sp := range kp.pools {
+		sp.Clear()
+		delete(kp.pools, key)
+	}
+}
+
+// subPool returns the sub-pool of key, creating it if needed.
+func (kp *KeyedPool) subPool(key string) *keyedSubPool {
+	kp.mu.RLock()
+	sp, ok := kp.pools[key]
+	kp.mu.RUnlock()
+	if ok {
+		return sp
+	}
+
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	if sp, ok = kp.pools[key]; ok {
+		return sp
+	}
+	sp = &keyedSubPool{}
+	sp.Pool = NewPool(func() interface{} {
+		return kp.create(sp, key)
+	}, kp.opts...)
+	kp.pools[key] = sp
+	return sp
+}
+
+// create is the factory of the sub-pool sp of key. It waits for the factory
+// call in flight for the key, if any, before it calls newFunc.
+func (kp *KeyedPool) create(sp *keyedSubPool, key string) interface{} {
+	f := &keyedFlight{done: make(chan struct{})}
+	for !sp.flight.CompareAndSwap(nil, f) {
+		if cur := sp.flight.Load(); cur != nil {
+			<-cur.done
+		}
+	}
+	defer func() {
+		sp.flight.Store(nil)
+		close(f.done)
+	}()
+	return kp.newFunc(key)
+}
+
+// maybeEvict runs an eviction sweep if at least one idle timeout passed since the last one.
+func (kp *KeyedPool) maybeEvict(now int64) {
+	if kp.idleTimeout <= 0 {
+		return
+	}
+	last := atomic.LoadInt64(&kp.lastSweep)
+	if now-last < int64(kp.idleTimeout) || !atomic.CompareAndSwapInt64(&kp.lastSweep, last, now) {
+		return
+	}
+	kp.evict(now)
+}
+
+// evict removes the sub-pools idle for longer than the idle timeout at now.
+func (kp *KeyedPool) evict(now int64) int {
+	if kp.idleTimeout <= 0 {
+		return 0
+	}
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	n := 0
+	for key, sp := range kp.pools {
+		if now-atomic.LoadInt64(&sp.lastUsed) > int64(kp.idleTimeout) {
+			sp.Clear()
+			delete(kp.pools, key)
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,99 @@
+package pool
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestKeyedPool tests that each key gets its own sub-pool and factory call.
+func TestKeyedPool(t *testing.T) {
+	kp := NewKeyedPool(func(key string) interface{} {
+		return key
+	}, 0)
+
+	if obj := kp.Get("a"); obj != "a" {
+		t.Errorf("Expected object for key a, got %v", obj)
+	}
+	if obj := kp.Get("b"); obj != "b" {
+		t.Errorf("Expected object for key b, got %v", obj)
+	}
+	kp.Put("a", "a")
+
+	if n := kp.KeyCount(); n != 2 {
+		t.Errorf("Expected 2 keys, got %d", n)
+	}
+	kp.Clear()
+	if n := kp.KeyCount(); n != 0 {
+		t.Errorf("Expected no keys after Clear, got %d", n)
+	}
+}
+
+// TestKeyedPoolEvict tests that idle keys are evicted.
+func TestKeyedPoolEvict(t *testing.T) {
+	kp := NewKeyedPool(func(key string) interface{} {
+		return key
+	}, time.Millisecond)
+
+	kp.Put("idle", "idle")
+	time.Sleep(5 * time.Millisecond)
+	kp.Put("busy", "busy")
+
+	if n := kp.Evict(); n != 1 {
+		t.Errorf("Expected 1 evicted key, got %d", n)
+	}
+	if n := kp.KeyCount(); n != 1 {
+		t.Errorf("Expected 1 remaining key, got %d", n)
+	}
+}
+
+// TestKeyedPoolSingleflight tests that concurrent misses of a key run one factory call at a time
+// and still receive objects of their own.
+func TestKeyedPoolSingleflight(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	kp := NewKeyedPool(func(key string) interface{} {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return new(int)
+	}, 0)
+	predictable(kp.subPool("cold").Pool)
+
+	objs := make([]interface{}, 8)
+	var wg sync.WaitGroup
+	for i := range objs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			objs[i] = kp.Get("cold")
+		}()
+	}
+	wg.Wait()
+	if maxRunning != 1 {
+		t.Errorf("Expected 1 factory call at a time for concurrent misses, got %d", maxRunning)
+	}
+	seen := make(map[interface{}]bool)
+	for _, obj := range objs {
+		if seen[obj] {
+			t.Fatal("Expected every caller to receive an object of its own")
+		}
+		seen[obj] = true
+	}
+
+	sp := kp.subPool("cold")
+	for _, obj := range objs {
+		kp.Put("cold", obj)
+	}
+	if n := idleCount(sp.Pool); n != len(objs) {
+		t.Errorf("Expected every object to return, got %d idle", n)
+	}
+	if n := sp.InUse(); n != 0 {
+		t.Errorf("Expected no object in use after every Put, got %d", n)
+	}
+}
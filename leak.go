@@ -0,0 +1,162 @@
+package pool
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"log/slog"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Leak describes an object that was not returned to the pool in time.
+type Leak struct {
+	// Object is the leaked object
+	Object interface{}
+	// CheckedOut is the time the object was retrieved
+	CheckedOut time.Time
+	// Stack is the stack trace of the Get call, empty for leases reported by their finalizer
+	Stack []byte
+	// Goroutine is the ID of the goroutine that called Get, zero if Stack is empty
+	Goroutine uint64
+}
+
+// checkout records a single outstanding object.
+type checkout struct {
+	leak  Leak
+	timer *time.Timer
+}
+
+// leakTracker tracks the objects handed out by Get until they are Put back.
+// The zero value is ready to use.
+type leakTracker struct {
+	count       int64
+	mu          sync.Mutex
+	outstanding map[uintptr]*checkout
+}
+
+// tracking reports whether any object is currently tracked.
+func (t *leakTracker) tracking() bool {
+	return atomic.LoadInt64(&t.count) > 0
+}
+
+// checkout starts tracking obj and arms a timer that reports it to report once
+// timeout passes.
+func (t *leakTracker) checkout(obj interface{}, timeout time.Duration, report func(Leak)) {
+	id, ok := objectID(obj)
+	if !ok {
+		return
+	}
+	stack := debug.Stack()
+	co := &checkout{
+		leak: Leak{
+			Object:     obj,
+			CheckedOut: time.Now(),
+			Stack:      stack,
+			Goroutine:  goroutineID(stack),
+		},
+	}
+	co.timer = time.AfterFunc(timeout, func() {
+		report(co.leak)
+	})
+
+	t.mu.Lock()
+	if t.outstanding == nil {
+		t.outstanding = make(map[uintptr]*checkout)
+	}
+	if old, ok := t.outstanding[id]; ok {
+		old.timer.Stop()
+	} else {
+		atomic.AddInt64(&t.count, 1)
+	}
+	t.outstanding[id] = co
+	t.mu.Unlock()
+}
+
+// checkin stops tracking obj.
+func (t *leakTracker) checkin(obj interface{}) {
+	id, ok := objectID(obj)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	if co, ok := t.outstanding[id]; ok {
+		co.timer.Stop()
+		delete(t.outstanding, id)
+		atomic.AddInt64(&t.count, -1)
+	}
+	t.mu.Unlock()
+}
+
+// snapshot returns the outstanding objects, the longest checked out first.
+func (t *leakTracker) snapshot() []Leak {
+	t.mu.Lock()
+	leaks := make([]Leak, 0, len(t.outstanding))
+	for _, co := range t.outstanding {
+		leaks = append(leaks, co.leak)
+	}
+	t.mu.Unlock()
+	sort.Slice(leaks, func(i, j int) bool {
+		return leaks[i].CheckedOut.Before(leaks[j].CheckedOut)
+	})
+	return leaks
+}
+
+// objectID returns the identity of obj if it is of a reference kind.
+func objectID(obj interface{}) (uintptr, bool) {
+	v := reflect.ValueOf(obj)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+	case reflect.Slice:
+		if v.Cap() == 0 {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	id := v.Pointer()
+	return id, id != 0
+}
+
+// reportLeak passes a leak report to the leak handler and the logger.
+func (p *Pool) reportLeak(l Leak) {
+	cfg := p.config()
+	p.log(cfg, cfg.levels().Leaks, "pool object was not returned",
+		slog.String("type", fmt.Sprintf("%T", l.Object)),
+		slog.Time("checked_out", l.CheckedOut),
+		slog.Uint64("goroutine", l.Goroutine))
+	cfg.leakHandler(l)
+}
+
+// logLeak is the default leak handler.
+func logLeak(l Leak) {
+	log.Printf("pool: object %T checked out at %s was not returned\n%s", l.Object, l.CheckedOut.Format(time.RFC3339Nano), l.Stack)
+}
+
+// goroutineID parses the ID of the goroutine from the header of its stack trace.
+func goroutineID(stack []byte) uint64 {
+	stack = bytes.TrimPrefix(stack, []byte("goroutine "))
+	var id uint64
+	for _, c := range stack {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
+
+// InUseDetails lists the objects currently checked out, the longest checked
+// out first, with the time, goroutine and stack trace of their Gets. It
+// requires WithLeakDetection and returns nil without it; objects of
+// non-reference kinds are not listed.
+func (p *Pool) InUseDetails() []Leak {
+	if p.config().leakTimeout <= 0 {
+		return nil
+	}
+	return p.leaks.snapshot()
+}
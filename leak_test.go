@@ -0,0 +1,92 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLeakDetection tests that objects not returned in time are reported.
+func TestLeakDetection(t *testing.T) {
+	leaks := make(chan Leak, 1)
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithLeakDetection(10*time.Millisecond), WithLeakHandler(func(l Leak) {
+		leaks <- l
+	}))
+
+	returned := p.Get()
+	leaked := p.Get()
+	p.Put(returned)
+
+	select {
+	case l := <-leaks:
+		if l.Object != leaked {
+			t.Error("Expected the leaked object to be reported")
+		}
+		if len(l.Stack) == 0 {
+			t.Error("Expected the report to include a stack trace")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a leak report")
+	}
+
+	select {
+	case <-leaks:
+		t.Error("Expected the returned object not to be reported")
+	case <-time.After(30 * time.Millisecond):
+	}
+}
+
+// TestObjectID tests which objects can be tracked.
+func TestObjectID(t *testing.T) {
+	cases := []struct {
+		obj interface{}
+		ok  bool
+	}{
+		{new(int), true},
+		{make(map[int]int), true},
+		{make([]byte, 1), true},
+		{make([]byte, 0), false},
+		{42, false},
+		{"s", false},
+	}
+	for _, c := range cases {
+		if _, ok := objectID(c.obj); ok != c.ok {
+			t.Errorf("objectID(%T) ok = %v, expected %v", c.obj, ok, c.ok)
+		}
+	}
+}
+
+// TestInUseDetails tests that the census lists the outstanding objects.
+func TestInUseDetails(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithLeakDetection(time.Hour))
+	a := p.Get()
+	b := p.Get()
+	p.Put(b)
+
+	if n := p.InUse(); n != 1 {
+		t.Errorf("Expected 1 object in use, got %d", n)
+	}
+	details := p.InUseDetails()
+	if len(details) != 1 || details[0].Object != a {
+		t.Fatalf("Expected the outstanding object to be listed, got %v", details)
+	}
+	if details[0].Goroutine == 0 {
+		t.Error("Expected the goroutine of the Get to be recorded")
+	}
+	if NewPool(func() interface{} { return new(int) }).InUseDetails() != nil {
+		t.Error("Expected no details without leak detection")
+	}
+}
+
+// TestGoroutineID tests parsing of the goroutine ID from a stack trace.
+func TestGoroutineID(t *testing.T) {
+	if id := goroutineID([]byte("goroutine 42 [running]:\nmain.main()")); id != 42 {
+		t.Errorf("Expected 42, got %d", id)
+	}
+	if id := goroutineID(nil); id != 0 {
+		t.Errorf("Expected 0 for an empty stack, got %d", id)
+	}
+}
@@ -0,0 +1,95 @@
+package pool
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// Lease is a handle to an object retrieved with Pool.Lease.
+// Releasing it returns the object to the pool.
+type Lease struct {
+	pool     *Pool
+	obj      interface{}
+	at       time.Time
+	released int32
+}
+
+// Lease retrieves an object from the pool together with a lease that returns it.
+// Release is idempotent, so it is safe to defer right after the call:
+//
+//	lease, obj := p.Lease()
+//	defer lease.Release()
+//
+// If the pool was created WithLeaseFinalizer, leases that become unreachable
+// without being released are reported to the leak handler.
+func (p *Pool) Lease() (*Lease, interface{}) {
+	cfg := p.config()
+	obj := p.Get()
+	l := &Lease{
+		pool: p,
+		obj:  obj,
+		at:   cfg.clock.Now(),
+	}
+	if cfg.leaseFinalizer {
+		runtime.SetFinalizer(l, (*Lease).finalize)
+	}
+	return l, obj
+}
+
+// Object returns the leased object.
+func (l *Lease) Object() interface{} {
+	return l.obj
+}
+
+// Release returns the leased object to the pool.
+// Only the first call has an effect.
+func (l *Lease) Release() {
+	if !atomic.CompareAndSwapInt32(&l.released, 0, 1) {
+		return
+	}
+	runtime.SetFinalizer(l, nil)
+	l.pool.Put(l.obj)
+}
+
+// Released reports whether the lease has been released.
+func (l *Lease) Released() bool {
+	return atomic.LoadInt32(&l.released) == 1
+}
+
+// finalize reports a lease that was dropped without being released and
+// reclaims its object if configured. Otherwise the object is discarded, so it
+// no longer holds an in-use slot, and replaced with a new one if configured.
+func (l *Lease) finalize() {
+	if !atomic.CompareAndSwapInt32(&l.released, 0, 1) {
+		return
+	}
+	cfg := l.pool.config()
+	l.pool.reportLeak(Leak{
+		Object:     l.obj,
+		CheckedOut: l.at,
+	})
+	if cfg.leaseReclaim {
+		l.pool.Put(l.obj)
+		return
+	}
+	if l.obj != nil {
+		l.pool.Discard(l.obj)
+	}
+	if cfg.leaseReplace {
+		l.pool.addNew(cfg)
+	}
+}
+
+// With retrieves an object, passes it to fn and puts it back afterwards,
+// even if fn returns an error or panics. It returns the error of fn, or that
+// of the Get without calling fn if no object could be retrieved.
+// fn must not keep a reference to the object after returning.
+func (p *Pool) With(fn func(obj interface{}) error) error {
+	obj, err := p.GetE()
+	if err != nil {
+		return err
+	}
+	defer p.Put(obj)
+	return fn(obj)
+}
@@ -0,0 +1,152 @@
+package pool
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestLease tests that releasing a lease returns its object exactly once.
+func TestLease(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+
+	lease, obj := p.Lease()
+	if lease.Object() != obj {
+		t.Error("Expected the lease to hold the returned object")
+	}
+	lease.Release()
+	lease.Release()
+
+	if !lease.Released() {
+		t.Error("Expected the lease to be released")
+	}
+	if n := idleCount(p); n != 1 {
+		t.Errorf("Expected 1 idle object after Release, got %d", n)
+	}
+}
+
+// TestLeaseFinalizer tests that dropped leases are reported and reclaimed.
+func TestLeaseFinalizer(t *testing.T) {
+	leaks := make(chan Leak, 1)
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithLeaseFinalizer(true), WithLeakHandler(func(l Leak) {
+		leaks <- l
+	})))
+
+	func() {
+		p.Lease()
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		runtime.GC()
+		select {
+		case <-leaks:
+			// The object is put back after the report
+			for i := 0; idleCount(p) != 1 && i < 100; i++ {
+				time.Sleep(time.Millisecond)
+			}
+			if n := idleCount(p); n != 1 {
+				t.Errorf("Expected the object to be reclaimed, got %d idle", n)
+			}
+			return
+		case <-deadline:
+			t.Fatal("Expected the dropped lease to be reported")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// TestLeaseReplace tests that dropped leases free their slot and are replaced.
+func TestLeaseReplace(t *testing.T) {
+	leaks := make(chan Leak, 1)
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithMaxInUse(1, false), WithLeaseReplace(), WithLeakHandler(func(l Leak) {
+		leaks <- l
+	})))
+
+	func() {
+		p.Lease()
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		runtime.GC()
+		select {
+		case <-leaks:
+			// The object is discarded and replaced after the report
+			for i := 0; idleCount(p) != 1 && i < 100; i++ {
+				time.Sleep(time.Millisecond)
+			}
+			if n := idleCount(p); n != 1 {
+				t.Errorf("Expected the object to be replaced, got %d idle", n)
+			}
+			if _, err := p.GetE(); err != nil {
+				t.Errorf("Expected the in-use slot to be freed, got %v", err)
+			}
+			return
+		case <-deadline:
+			t.Fatal("Expected the dropped lease to be reported")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// TestWith tests that With puts the object back on success, error and panic.
+func TestWith(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	errTest := errors.New("test")
+
+	if err := p.With(func(obj interface{}) error {
+		return nil
+	}); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+	if err := p.With(func(obj interface{}) error {
+		return errTest
+	}); err != errTest {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the panic to be propagated")
+			}
+		}()
+		p.With(func(obj interface{}) error {
+			panic("test")
+		})
+	}()
+
+	if n := idleCount(p); n != 1 {
+		t.Errorf("Expected the object to be put back every time, got %d idle", n)
+	}
+}
+
+// TestWithGetError tests that With returns the error of a failed Get without calling fn.
+func TestWithGetError(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithMaxInUse(1, false)))
+	held := p.Get()
+
+	called := false
+	err := p.With(func(obj interface{}) error {
+		called = true
+		return nil
+	})
+	if err != ErrExhausted || called {
+		t.Errorf("Expected ErrExhausted without a call, got %v and called %v", err, called)
+	}
+	p.Put(held)
+	if n := idleCount(p); n != 1 {
+		t.Errorf("Expected only the held object to be idle, got %d", n)
+	}
+}
@@ -0,0 +1,107 @@
+package pool
+
+import "sync/atomic"
+
+// localBatch is the default number of objects a Local moves between its cache
+// and the shards at once.
+const localBatch = 16
+
+// Local is a per-worker cache in front of a Pool for long-lived goroutines.
+// Its Get and Put work on a private slice without synchronization, refilling
+// from and spilling to the shared shards in batches.
+//
+// A Local must not be used by more than one goroutine at a time. Objects in its
+// cache count as checked out of the pool until they are spilled or flushed, so
+// call Flush when the worker exits. Pools with an in-use limit, leak
+// detection, clone mode, zeroing or the sanitizer need per-object handling, so
+// their Locals pass every call through to the pool.
+type Local struct {
+	pool  *Pool
+	objs  []interface{}
+	batch int
+}
+
+// Local returns a new per-worker cache of the pool.
+func (p *Pool) Local() *Local {
+	return &Local{pool: p, batch: localBatch}
+}
+
+// Get retrieves an object from the cache, refilling it from the pool if it is empty.
+func (l *Local) Get() interface{} {
+	if len(l.objs) == 0 && !l.passThrough() {
+		l.refill()
+	}
+	if n := len(l.objs); n > 0 {
+		obj := l.objs[n-1]
+		l.objs[n-1] = nil
+		l.objs = l.objs[:n-1]
+		return obj
+	}
+	return l.pool.Get()
+}
+
+// Put returns an object to the cache, spilling a batch to the pool if it is full.
+// If the object is nil, it will be ignored.
+func (l *Local) Put(obj interface{}) {
+	if obj == nil {
+		return
+	}
+	if l.passThrough() {
+		l.pool.Put(obj)
+		return
+	}
+	if len(l.objs) >= 2*l.batch {
+		l.spill(l.batch)
+	}
+	l.objs = append(l.objs, obj)
+}
+
+// Flush returns all cached objects to the pool.
+func (l *Local) Flush() {
+	l.spill(len(l.objs))
+}
+
+// passThrough reports whether calls must go straight to the pool.
+func (l *Local) passThrough() bool {
+	cfg := l.pool.config()
+	return cfg.maxInUse > 0 || cfg.leakTimeout > 0 || cfg.clone != nil ||
+		cfg.zeroFunc != nil || cfg.sanitizeHandler != nil
+}
+
+// refill moves up to a batch of idle objects of the pool into the cache,
+// taking a single shard lock.
+func (l *Local) refill() {
+	p := l.pool
+	if atomic.LoadInt32(&p.state) != stateOpen {
+		return
+	}
+	cfg := p.config()
+	shardID := p.pickShard(cfg)
+	shard := &p.shards[shardID]
+	epoch := atomic.LoadUint64(&p.epoch)
+	n := 0
+	for _, e := range shard.popN(l.batch, cfg) {
+		if e.epoch < epoch {
+			p.evicted(e, cfg)
+			continue
+		}
+		l.objs = append(l.objs, p.popped(e, shardID, cfg))
+		n++
+	}
+	if n > 0 {
+		atomic.AddUint64(&p.gets, uint64(n))
+		atomic.AddUint64(&shard.gets, uint64(n))
+		atomic.AddUint64(&shard.hits, uint64(n))
+		atomic.AddInt64(&shard.checkedOut, int64(n))
+	}
+}
+
+// spill returns the n least recently cached objects to the pool.
+func (l *Local) spill(n int) {
+	for _, obj := range l.objs[:n] {
+		l.pool.Put(obj)
+	}
+	rest := copy(l.objs, l.objs[n:])
+	clear(l.objs[rest:])
+	l.objs = l.objs[:rest]
+}
@@ -0,0 +1,105 @@
+package pool
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestLocal tests that a Local serves objects from its cache and spills and flushes to the pool.
+func TestLocal(t *testing.T) {
+	created := 0
+	p := unsanitized(predictable(NewPool(func() interface{} {
+		created++
+		return new(int)
+	})))
+	l := p.Local()
+
+	obj := l.Get()
+	l.Put(obj)
+	if got := l.Get(); got != obj {
+		t.Error("Expected the cached object to be reused")
+	}
+	if created != 1 {
+		t.Errorf("Expected 1 created object, got %d", created)
+	}
+
+	for i := 0; i < 2*localBatch+1; i++ {
+		l.Put(new(int))
+	}
+	if n := len(l.objs); n != localBatch+1 {
+		t.Errorf("Expected a batch to be spilled, got %d cached", n)
+	}
+	if n := idleCount(p); n != localBatch {
+		t.Errorf("Expected %d idle objects in the pool, got %d", localBatch, n)
+	}
+
+	l.Flush()
+	if n := len(l.objs); n != 0 {
+		t.Errorf("Expected an empty cache after Flush, got %d", n)
+	}
+	if n := idleCount(p); n != 2*localBatch+1 {
+		t.Errorf("Expected %d idle objects, got %d", 2*localBatch+1, n)
+	}
+}
+
+// TestLocalRefill tests that an empty Local refills a batch from one shard.
+func TestLocalRefill(t *testing.T) {
+	p := unsanitized(predictable(NewPool(func() interface{} {
+		return new(int)
+	})))
+	l := p.Local()
+	for id := range p.shards {
+		for i := 0; i < localBatch+4; i++ {
+			p.shards[id].push(entry{obj: new(int)}, shardCap, nil)
+		}
+	}
+
+	l.Get()
+	if n := len(l.objs); n != localBatch-1 {
+		t.Errorf("Expected %d cached objects after a refill, got %d", localBatch-1, n)
+	}
+	if n := p.checkedOut(); n != localBatch {
+		t.Errorf("Expected the refilled objects to count as checked out, got %d", n)
+	}
+	l.Flush()
+	if n := p.checkedOut(); n != 1 {
+		t.Errorf("Expected 1 checked-out object after Flush, got %d", n)
+	}
+}
+
+// TestLocalPassThrough tests that Locals of pools with bookkeeping call the pool directly.
+func TestLocalPassThrough(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithLeakDetection(time.Hour)))
+	l := p.Local()
+	l.Put(l.Get())
+
+	if n := len(l.objs); n != 0 {
+		t.Errorf("Expected no cached objects, got %d", n)
+	}
+	if n := idleCount(p); n != 1 {
+		t.Errorf("Expected the object to go back to the pool, got %d idle", n)
+	}
+}
+
+// TestLocalPassThroughZero tests that Locals of pools that wipe or poison objects call the pool directly.
+func TestLocalPassThroughZero(t *testing.T) {
+	opts := []Option{WithZeroOnPut(), WithSanitizer(func(obj interface{}) {})}
+	for _, opt := range opts {
+		p := predictable(NewPool(func() interface{} {
+			return make([]byte, 0, 8)
+		}, opt))
+		l := p.Local()
+		b := append(l.Get().([]byte), "secret"...)
+		l.Put(b)
+
+		if n := len(l.objs); n != 0 {
+			t.Errorf("Expected no cached objects, got %d", n)
+		}
+		if bytes.Contains(b[:cap(b)], []byte("secret")) {
+			t.Error("Expected the object to be wiped on Put")
+		}
+	}
+}
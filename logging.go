@@ -0,0 +1,69 @@
+package pool
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Minimum interval between two reports of dropped objects
+const dropLogInterval = time.Second
+
+// LogLevels are the levels at which a pool logs its events, see WithLogger.
+type LogLevels struct {
+	// Drops is the level of the reports of returned objects that were dropped
+	// because the pool was full, logged at most once per second while drops last
+	Drops slog.Level
+	// FactoryErrors is the level of factory failures
+	FactoryErrors slog.Level
+	// Leaks is the level of leak reports, see WithLeakDetection
+	Leaks slog.Level
+	// Evictions is the level of idle objects evicted by policies, overflow or trimming
+	Evictions slog.Level
+}
+
+// DefaultLogLevels are the levels used by WithLogger unless set with WithLogLevels.
+var DefaultLogLevels = LogLevels{
+	Drops:         slog.LevelWarn,
+	FactoryErrors: slog.LevelError,
+	Leaks:         slog.LevelWarn,
+	Evictions:     slog.LevelDebug,
+}
+
+// levels returns the log levels of c.
+func (c *config) levels() *LogLevels {
+	if c.logLevels != nil {
+		return c.logLevels
+	}
+	return &DefaultLogLevels
+}
+
+// log emits an event to the logger of cfg, if any, tagged with the name of
+// the pool if it has one.
+func (p *Pool) log(cfg *config, level slog.Level, msg string, attrs ...slog.Attr) {
+	if cfg.logger == nil {
+		return
+	}
+	ctx := context.Background()
+	if !cfg.logger.Enabled(ctx, level) {
+		return
+	}
+	if p.name != "" {
+		attrs = append(attrs, slog.String("pool", p.name))
+	}
+	cfg.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logDrops reports the objects dropped since the last report, unless that was
+// less than dropLogInterval ago.
+func (p *Pool) logDrops(cfg *config) {
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&p.dropLogAt)
+	if now-last < int64(dropLogInterval) || !atomic.CompareAndSwapInt64(&p.dropLogAt, last, now) {
+		return
+	}
+	drops := atomic.LoadUint64(&p.drops)
+	n := drops - atomic.SwapUint64(&p.dropsLogged, drops)
+	p.log(cfg, cfg.levels().Drops, "pool dropping returned objects", slog.Uint64("drops", n))
+}
@@ -0,0 +1,50 @@
+package pool
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestLogger tests that factory errors and sustained drops are logged.
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	p := predictable(NewPoolE(func() (interface{}, error) {
+		return nil, errors.New("dial failed")
+	}, WithLogger(logger)))
+	p.updateConfig(func(c *config) {
+		c.shardCap = 0
+	})
+
+	p.GetE()
+	for i := 0; i < 3; i++ {
+		p.Put(new(int))
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "level=ERROR") || !strings.Contains(out, "dial failed") {
+		t.Errorf("Expected the factory error to be logged, got %q", out)
+	}
+	if n := strings.Count(out, "pool dropping returned objects"); n != 1 {
+		t.Errorf("Expected 1 drop report within the interval, got %d in %q", n, out)
+	}
+}
+
+// TestLogLevels tests that events below the logger's level are not logged.
+func TestLogLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	levels := DefaultLogLevels
+	levels.FactoryErrors = slog.LevelDebug
+	p := predictable(NewPoolE(func() (interface{}, error) {
+		return nil, errors.New("dial failed")
+	}, WithLogger(logger), WithLogLevels(levels)))
+
+	p.GetE()
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing to be logged, got %q", buf.String())
+	}
+}
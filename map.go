@@ -0,0 +1,60 @@
+package pool
+
+// Default max number of entries of maps retained by MapPool
+const defaultMapMaxLen = 1024
+
+// Number of hash table slots per entry of maxLen a retained map may have,
+// leaving room for the load factor and the growth steps
+const mapSlotsPerEntry = 4
+
+// Slots of the hash table of a small map, a single group
+const minMapSlots = 8
+
+// MapPool is a pool of map[K]V.
+//
+// Maps are cleared on Put. A cleared map keeps the hash table it grew to, so
+// maps that hold more than maxLen entries, or whose table grew beyond that
+// size before they were emptied, are dropped instead of retained, preventing
+// a single burst from pinning a huge table in the pool.
+type MapPool[K comparable, V any] struct {
+	maxLen int
+	pool   *Pool
+}
+
+// NewMapPool creates a new map pool.
+// maxLen is the max number of entries a map may hold when it is returned; if maxLen <= 0 a default of 1024 is used.
+func NewMapPool[K comparable, V any](maxLen int) *MapPool[K, V] {
+	if maxLen <= 0 {
+		maxLen = defaultMapMaxLen
+	}
+	return &MapPool[K, V]{
+		maxLen: maxLen,
+		pool: NewPool(func() interface{} {
+			return make(map[K]V)
+		}),
+	}
+}
+
+// Get retrieves an empty map from the pool.
+func (mp *MapPool[K, V]) Get() map[K]V {
+	return mp.pool.Get().(map[K]V)
+}
+
+// Put clears a map and returns it to the pool.
+// Maps holding more than maxLen entries, or that ever held many more, are
+// dropped.
+func (mp *MapPool[K, V]) Put(m map[K]V) {
+	if m == nil || len(m) > mp.maxLen {
+		return
+	}
+	if slots, ok := mapSlots(m); ok && slots > max(mapSlotsPerEntry*mp.maxLen, minMapSlots) {
+		return
+	}
+	clear(m)
+	mp.pool.Put(m)
+}
+
+// Clear clears all maps from the pool.
+func (mp *MapPool[K, V]) Clear() {
+	mp.pool.Clear()
+}
@@ -0,0 +1,82 @@
+package pool
+
+import "testing"
+
+// TestMapPool tests that returned maps are cleared.
+func TestMapPool(t *testing.T) {
+	mp := NewMapPool[string, int](0)
+	predictable(mp.pool)
+
+	m := mp.Get()
+	m["a"] = 1
+	mp.Put(m)
+
+	if len(m) != 0 {
+		t.Error("Expected map to be cleared on Put")
+	}
+	if n := idleCount(mp.pool); n != 1 {
+		t.Errorf("Expected 1 idle map, got %d", n)
+	}
+}
+
+// TestMapPoolMaxLen tests that maps above the threshold are dropped.
+func TestMapPoolMaxLen(t *testing.T) {
+	mp := NewMapPool[int, int](2)
+
+	m := mp.Get()
+	for i := 0; i < 3; i++ {
+		m[i] = i
+	}
+	mp.Put(m)
+
+	if n := idleCount(mp.pool); n != 0 {
+		t.Errorf("Expected oversized map to be dropped, got %d idle", n)
+	}
+}
+
+// TestMapPoolPeak tests that maps emptied after growing far beyond the threshold are dropped.
+func TestMapPoolPeak(t *testing.T) {
+	if _, ok := mapSlots(map[int]int{}); !ok {
+		t.Skip("map table sizes are unknown in this build")
+	}
+	mp := NewMapPool[int, int](64)
+	predictable(mp.pool)
+
+	m := mp.Get()
+	for i := 0; i < 64; i++ {
+		m[i] = i
+	}
+	clear(m)
+	mp.Put(m)
+	if n := idleCount(mp.pool); n != 1 {
+		t.Fatalf("Expected a map within the threshold to be retained, got %d idle", n)
+	}
+
+	m = mp.Get()
+	for i := 0; i < 100000; i++ {
+		m[i] = i
+	}
+	clear(m)
+	mp.Put(m)
+	if n := idleCount(mp.pool); n != 0 {
+		t.Errorf("Expected a map that grew beyond the threshold to be dropped, got %d idle", n)
+	}
+}
+
+// TestMapSlots tests that the table size of a map is kept by clear.
+func TestMapSlots(t *testing.T) {
+	m := make(map[int]int)
+	small, ok := mapSlots(m)
+	if !ok {
+		t.Skip("map table sizes are unknown in this build")
+	}
+	for i := 0; i < 10000; i++ {
+		m[i] = i
+	}
+	grown, _ := mapSlots(m)
+	clear(m)
+	cleared, _ := mapSlots(m)
+	if grown < 10000 || cleared != grown || small > minMapSlots {
+		t.Errorf("Expected slots of at most %d, at least 10000 and unchanged by clear, got %d, %d and %d", minMapSlots, small, grown, cleared)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !goexperiment.swissmap && !go1.26
+
+package pool
+
+// mapSlots reports that the hash table size of m cannot be determined with
+// the bucket-based maps of this build.
+func mapSlots[K comparable, V any](m map[K]V) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,43 @@
+//go:build goexperiment.swissmap || go1.26
+
+package pool
+
+import "unsafe"
+
+// swissMap mirrors the head of the header of a Swiss table map, see
+// internal/runtime/maps.Map.
+type swissMap struct {
+	used   uint64
+	seed   uintptr
+	dirPtr unsafe.Pointer
+	dirLen int
+}
+
+// swissTable mirrors the head of a table of a Swiss table map, see
+// internal/runtime/maps.table.
+type swissTable struct {
+	used     uint16
+	capacity uint16
+}
+
+// mapSlots returns the number of slots of the hash table of m, which clear
+// and delete never shrink, and whether it could be determined.
+func mapSlots[K comparable, V any](m map[K]V) (int, bool) {
+	h := *(**swissMap)(unsafe.Pointer(&m))
+	if h == nil {
+		return 0, true
+	}
+	if h.dirLen == 0 {
+		return minMapSlots, true
+	}
+	// Directory entries of the same table are adjacent
+	slots := 0
+	var last *swissTable
+	for _, t := range unsafe.Slice((**swissTable)(h.dirPtr), h.dirLen) {
+		if t != last {
+			slots += int(t.capacity)
+			last = t
+		}
+	}
+	return slots, true
+}
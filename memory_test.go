@@ -0,0 +1,31 @@
+package pool
+
+import "testing"
+
+// TestMaxMemory tests that the pool bounds the total size of idle objects.
+func TestMaxMemory(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return make([]byte, 0, 1024)
+	}, WithMaxMemory(4096), WithSizer(func(obj interface{}) int {
+		return cap(obj.([]byte))
+	})))
+
+	p.Put(make([]byte, 0, 3000))
+	p.Put(make([]byte, 0, 2000))
+	p.Put(make([]byte, 0, 1000))
+	if n := idleCount(p); n != 2 {
+		t.Errorf("Expected 2 idle objects within the limit, got %d", n)
+	}
+	if got := p.RetainedBytes(); got != 4000 {
+		t.Errorf("Expected 4000 retained bytes, got %d", got)
+	}
+
+	p.Get()
+	if got := p.RetainedBytes(); got != 3000 {
+		t.Errorf("Expected 3000 retained bytes after Get, got %d", got)
+	}
+	p.Clear()
+	if got := p.RetainedBytes(); got != 0 {
+		t.Errorf("Expected no retained bytes after Clear, got %d", got)
+	}
+}
@@ -0,0 +1,56 @@
+package pool
+
+import "reflect"
+
+// Message is the part of a protobuf message a MessagePool relies on. Messages
+// generated by protoc-gen-go implement it, and their Reset is what
+// proto.Reset calls, so the pool works without depending on a protobuf
+// module.
+type Message interface {
+	Reset()
+}
+
+// MessagePool is a pool of messages of type T, usually pointers to generated
+// protobuf message structs. Put resets a message before it is retained, so a
+// message from Get is always empty.
+type MessagePool[T Message] struct {
+	pool *Pool
+}
+
+// NewMessagePool creates a new message pool. newFn creates an empty message,
+// e.g. func() *pb.Request { return new(pb.Request) }.
+func NewMessagePool[T Message](newFn func() T, opts ...Option) *MessagePool[T] {
+	return &MessagePool[T]{pool: NewPool(func() interface{} {
+		return newFn()
+	}, opts...)}
+}
+
+// Get retrieves an empty message.
+func (mp *MessagePool[T]) Get() (T, error) {
+	obj, err := mp.pool.GetE()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return obj.(T), nil
+}
+
+// Put resets a message and returns it to the pool. The message, and any
+// message, slice or map reached from it, must not be used after Put.
+func (mp *MessagePool[T]) Put(m T) {
+	if isNil(m) {
+		return
+	}
+	m.Reset()
+	mp.pool.Put(m)
+}
+
+// isNil reports whether m is nil or a nil pointer, on which the Reset of a
+// generated message panics.
+func isNil(m Message) bool {
+	if m == nil {
+		return true
+	}
+	v := reflect.ValueOf(m)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
@@ -0,0 +1,43 @@
+package pool
+
+import "testing"
+
+// testMessage mimics a generated protobuf message.
+type testMessage struct {
+	Name string
+	Tags []string
+}
+
+func (m *testMessage) Reset() { *m = testMessage{} }
+
+// TestMessagePool tests that messages are reset on Put.
+func TestMessagePool(t *testing.T) {
+	mp := NewMessagePool(func() *testMessage { return new(testMessage) })
+	predictable(mp.pool)
+
+	m, err := mp.Get()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	m.Name, m.Tags = "req", []string{"a"}
+	mp.Put(m)
+
+	m2, _ := mp.Get()
+	if m2 != m {
+		t.Error("Expected the message to be reused")
+	}
+	if m2.Name != "" || m2.Tags != nil {
+		t.Errorf("Expected an empty message, got %+v", *m2)
+	}
+}
+
+// TestMessagePoolNil tests that Put ignores nil messages.
+func TestMessagePoolNil(t *testing.T) {
+	mp := NewMessagePool(func() *testMessage { return new(testMessage) })
+	predictable(mp.pool)
+
+	mp.Put(nil)
+	if n := idleCount(mp.pool); n != 0 {
+		t.Errorf("Expected the nil message to be ignored, got %d idle", n)
+	}
+}
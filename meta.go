@@ -0,0 +1,101 @@
+package pool
+
+import (
+	"sync"
+	"time"
+)
+
+// ObjectInfo describes an idle object of the pool.
+// Without WithObjectMetadata only Object, Shard and Priority are set.
+type ObjectInfo struct {
+	// Object is the idle object
+	Object interface{}
+	// Shard is the index of the shard holding the object
+	Shard int
+	// Created is the time the object was created by the factory
+	Created time.Time
+	// LastUsed is the time the object was last returned to the pool
+	LastUsed time.Time
+	// Uses is the number of times the object was handed out
+	Uses int
+	// Priority is the priority class the object was returned with, see PutPriority
+	Priority int
+}
+
+// objectMeta is the metadata of a single object.
+type objectMeta struct {
+	created  time.Time
+	lastUsed time.Time
+	uses     int
+	// Epoch of the pool when the object was created, see Pool.Invalidate
+	epoch uint64
+	// Shard the object was last taken from, see WithAffinity
+	home shardHint
+}
+
+// metaTracker keeps the metadata of objects while they are checked out.
+// Idle objects carry their metadata in their shard entry.
+// The zero value is ready to use.
+type metaTracker struct {
+	mu  sync.Mutex
+	out map[uintptr]*objectMeta
+}
+
+// checkout counts a use of obj and keeps m until obj is checked in.
+func (t *metaTracker) checkout(obj interface{}, m *objectMeta) {
+	m.uses++
+	id, ok := objectID(obj)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	if t.out == nil {
+		t.out = make(map[uintptr]*objectMeta)
+	}
+	t.out[id] = m
+	t.mu.Unlock()
+}
+
+// checkin returns the metadata of obj, updated to have been last used at now.
+// Objects that are not tracked get new metadata created at now in epoch.
+func (t *metaTracker) checkin(obj interface{}, now time.Time, epoch uint64) *objectMeta {
+	var m *objectMeta
+	if id, ok := objectID(obj); ok {
+		t.mu.Lock()
+		m = t.out[id]
+		delete(t.out, id)
+		t.mu.Unlock()
+	}
+	if m == nil {
+		m = &objectMeta{created: now, epoch: epoch}
+	}
+	m.lastUsed = now
+	return m
+}
+
+// info returns the ObjectInfo of e, without its shard.
+func (e entry) info() ObjectInfo {
+	info := ObjectInfo{Object: e.obj, Priority: e.priority}
+	if e.meta != nil {
+		info.Created = e.meta.created
+		info.LastUsed = e.meta.lastUsed
+		info.Uses = e.meta.uses
+	}
+	return info
+}
+
+// Inspect calls fn for every idle object, shard by shard under the shard's lock.
+// fn must not call back into the pool.
+func (p *Pool) Inspect(fn func(ObjectInfo)) {
+	for i := range p.shards {
+		shard := &p.shards[i]
+		shard.mu.Lock()
+		shard.eachLocked(func(e entry) bool {
+			info := e.info()
+			info.Shard = i
+			fn(info)
+			return true
+		})
+		shard.unlock()
+	}
+}
@@ -0,0 +1,56 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// TestObjectMetadata tests that metadata survives checkouts.
+func TestObjectMetadata(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithObjectMetadata()))
+
+	start := time.Now()
+	obj := p.Get()
+	p.Put(obj)
+	if p.Get() != obj {
+		t.Fatal("Expected Get to return the pooled object")
+	}
+	time.Sleep(time.Millisecond)
+	p.Put(obj)
+
+	var infos []ObjectInfo
+	p.Inspect(func(info ObjectInfo) {
+		infos = append(infos, info)
+	})
+	if len(infos) != 1 {
+		t.Fatalf("Expected 1 idle object, got %d", len(infos))
+	}
+	info := infos[0]
+	if info.Object != obj || info.Uses != 2 {
+		t.Errorf("Expected the object to have been used twice, got %+v", info)
+	}
+	if info.Created.Before(start) || !info.LastUsed.After(info.Created) {
+		t.Errorf("Unexpected timestamps: %+v", info)
+	}
+}
+
+// TestInspectWithoutMetadata tests that Inspect reports objects without metadata.
+func TestInspectWithoutMetadata(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	p.Put(new(int))
+
+	n := 0
+	p.Inspect(func(info ObjectInfo) {
+		if info.Object == nil || !info.Created.IsZero() {
+			t.Errorf("Unexpected info: %+v", info)
+		}
+		n++
+	})
+	if n != 1 {
+		t.Errorf("Expected 1 idle object, got %d", n)
+	}
+}
@@ -0,0 +1,136 @@
+package pool
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// MmapPool is a pool of large fixed-size byte slices whose memory is mapped
+// directly from the operating system instead of allocated on the Go heap.
+//
+// The buffers are invisible to the GC, so multi-GB pools add nothing to heap
+// size or GC pacing, and their memory is returned to the OS whenever a buffer
+// leaves the pool: when it is dropped by a full shard, evicted, trimmed,
+// cleared or drained. Buffers must not be used after being returned, and
+// buffers that are never returned stay mapped. On platforms without a mapping
+// backend the buffers are allocated on the heap.
+type MmapPool struct {
+	size int
+	pool *Pool
+
+	mu     sync.Mutex
+	live   map[uintptr]struct{}
+	mapped int64
+}
+
+// NewMmapPool creates a new pool of buffers of size bytes, rounded up to the
+// page size.
+func NewMmapPool(size int, opts ...Option) *MmapPool {
+	if size <= 0 {
+		panic("invalid mmap pool buffer size")
+	}
+	page := os.Getpagesize()
+	mp := &MmapPool{
+		size: (size + page - 1) / page * page,
+		live: make(map[uintptr]struct{}),
+	}
+	opts = append(opts, WithDestructor(func(obj interface{}) {
+		mp.free(obj.([]byte))
+	}))
+	mp.pool = NewPoolE(func() (interface{}, error) {
+		return mp.alloc()
+	}, opts...)
+	return mp
+}
+
+// alloc maps a new buffer.
+func (mp *MmapPool) alloc() ([]byte, error) {
+	b, err := mmapAlloc(mp.size)
+	if err != nil {
+		return nil, err
+	}
+	mp.mu.Lock()
+	mp.live[bufferAddr(b)] = struct{}{}
+	mp.mu.Unlock()
+	atomic.AddInt64(&mp.mapped, int64(mp.size))
+	return b, nil
+}
+
+// free unmaps a buffer of the pool. Buffers that are not mapped by the pool,
+// e.g. because they were already freed, are ignored.
+func (mp *MmapPool) free(b []byte) {
+	if !mp.owns(b, true) {
+		return
+	}
+	atomic.AddInt64(&mp.mapped, -int64(mp.size))
+	_ = mmapFree(b[:cap(b)])
+}
+
+// owns reports whether b is a live buffer of the pool and, if forget is set,
+// stops tracking it.
+func (mp *MmapPool) owns(b []byte, forget bool) bool {
+	if cap(b) != mp.size {
+		return false
+	}
+	addr := bufferAddr(b)
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	_, ok := mp.live[addr]
+	if ok && forget {
+		delete(mp.live, addr)
+	}
+	return ok
+}
+
+// bufferAddr returns the address of the backing array of b.
+func bufferAddr(b []byte) uintptr {
+	return uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+}
+
+// Get retrieves a buffer of the pool's size, mapping a new one if none is idle.
+// The contents of reused buffers are not cleared.
+func (mp *MmapPool) Get() ([]byte, error) {
+	obj, err := mp.pool.GetE()
+	if err != nil {
+		return nil, err
+	}
+	return obj.([]byte), nil
+}
+
+// Put returns a buffer to the pool, unmapping it if it is not retained.
+// Slices that were not retrieved from the pool are ignored.
+func (mp *MmapPool) Put(b []byte) {
+	if !mp.owns(b, false) {
+		return
+	}
+	if !mp.pool.PutCheck(b[:cap(b)]) {
+		mp.free(b)
+	}
+}
+
+// Size returns the size of the pool's buffers.
+func (mp *MmapPool) Size() int {
+	return mp.size
+}
+
+// Mapped returns the number of bytes currently mapped by the pool, idle or
+// checked out.
+func (mp *MmapPool) Mapped() int64 {
+	return atomic.LoadInt64(&mp.mapped)
+}
+
+// Clear unmaps all idle buffers.
+func (mp *MmapPool) Clear() {
+	mp.pool.ClearFunc(func(obj interface{}) {
+		mp.free(obj.([]byte))
+	})
+}
+
+// Drain shuts the pool down like Pool.Drain, unmapping the idle buffers and
+// every buffer returned afterwards.
+func (mp *MmapPool) Drain(ctx context.Context) error {
+	return mp.pool.Drain(ctx)
+}
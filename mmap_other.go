@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package pool
+
+// mmapAlloc allocates size bytes on the heap on platforms without a mapping
+// backend.
+func mmapAlloc(size int) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+// mmapFree leaves heap-allocated memory to the GC.
+func mmapFree(b []byte) error {
+	return nil
+}
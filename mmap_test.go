@@ -0,0 +1,63 @@
+package pool
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+// TestMmapPool tests that buffers are reused and unmapped when they leave the pool.
+func TestMmapPool(t *testing.T) {
+	mp := NewMmapPool(1)
+	predictable(mp.pool)
+	if mp.Size() != os.Getpagesize() {
+		t.Errorf("Expected the size to be rounded up to a page, got %d", mp.Size())
+	}
+
+	b, err := mp.Get()
+	if err != nil || len(b) != mp.Size() {
+		t.Fatalf("Expected a mapped buffer, got %d bytes, %v", len(b), err)
+	}
+	b[0] = 1
+	mp.Put(b)
+	if c, _ := mp.Get(); &c[0] != &b[0] {
+		t.Error("Expected the buffer to be reused")
+	}
+	mp.Put(b)
+
+	mp.Put(make([]byte, mp.Size()))
+	if n := idleCount(mp.pool); n != 1 {
+		t.Errorf("Expected foreign buffers to be ignored, got %d idle", n)
+	}
+	mp.Clear()
+	if n := mp.Mapped(); n != 0 {
+		t.Errorf("Expected Clear to unmap the buffers, got %d bytes mapped", n)
+	}
+}
+
+// TestMmapPoolDrop tests that buffers dropped by a full pool are unmapped once.
+func TestMmapPoolDrop(t *testing.T) {
+	mp := NewMmapPool(1)
+	predictable(mp.pool)
+	mp.pool.updateConfig(func(c *config) {
+		c.shardCap = 0
+	})
+
+	b, _ := mp.Get()
+	mp.Put(b)
+	mp.Put(b)
+	if n := mp.Mapped(); n != 0 {
+		t.Errorf("Expected the dropped buffer to be unmapped, got %d bytes mapped", n)
+	}
+
+	b, _ = mp.Get()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := mp.Drain(ctx); err != context.Canceled {
+		t.Errorf("Expected Drain to give up on the checked-out buffer, got %v", err)
+	}
+	mp.Put(b)
+	if n := mp.Mapped(); n != 0 {
+		t.Errorf("Expected buffers returned after Drain to be unmapped, got %d bytes mapped", n)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build unix
+
+package pool
+
+import "syscall"
+
+// mmapAlloc maps size bytes of anonymous, private memory.
+func mmapAlloc(size int) ([]byte, error) {
+	return syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+}
+
+// mmapFree unmaps memory mapped by mmapAlloc.
+func mmapFree(b []byte) error {
+	return syscall.Munmap(b)
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package pool
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	memCommit     = 0x1000
+	memReserve    = 0x2000
+	memRelease    = 0x8000
+	pageReadWrite = 0x04
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procVirtualAlloc = kernel32.NewProc("VirtualAlloc")
+	procVirtualFree  = kernel32.NewProc("VirtualFree")
+)
+
+// mmapAlloc commits size bytes of private memory with VirtualAlloc.
+func mmapAlloc(size int) ([]byte, error) {
+	addr, _, err := procVirtualAlloc.Call(0, uintptr(size), memCommit|memReserve, pageReadWrite)
+	if addr == 0 {
+		return nil, err
+	}
+	return unsafe.Slice(*(**byte)(unsafe.Pointer(&addr)), size), nil
+}
+
+// mmapFree releases memory committed by mmapAlloc.
+func mmapFree(b []byte) error {
+	ok, _, err := procVirtualFree.Call(uintptr(unsafe.Pointer(unsafe.SliceData(b))), 0, memRelease)
+	if ok == 0 {
+		return err
+	}
+	return nil
+}
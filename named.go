@@ -0,0 +1,72 @@
+package pool
+
+import (
+	"sort"
+	"sync"
+)
+
+// named is the process-global registry of named pools.
+var named struct {
+	mu    sync.RWMutex
+	pools map[string]*Pool
+}
+
+// NewNamedPool creates a new object pool like NewPool and registers it under
+// name in the process-global registry, see Each. Registering a name twice
+// replaces its pool.
+func NewNamedPool(name string, fn func() interface{}, opts ...Option) *Pool {
+	if fn == nil {
+		panic("newFunc cannot be nil")
+	}
+	p := newPool(name, func() (interface{}, error) {
+		return fn(), nil
+	}, opts)
+	named.mu.Lock()
+	if named.pools == nil {
+		named.pools = make(map[string]*Pool)
+	}
+	named.pools[name] = p
+	named.mu.Unlock()
+	return p
+}
+
+// Name returns the name of a pool created with NewNamedPool, or "".
+func (p *Pool) Name() string {
+	return p.name
+}
+
+// Lookup returns the named pool registered under name, if any.
+func Lookup(name string) (*Pool, bool) {
+	named.mu.RLock()
+	defer named.mu.RUnlock()
+	p, ok := named.pools[name]
+	return p, ok
+}
+
+// Unregister removes the named pool registered under name from the registry.
+// The pool itself keeps working.
+func Unregister(name string) {
+	named.mu.Lock()
+	delete(named.pools, name)
+	named.mu.Unlock()
+}
+
+// Each calls fn for every named pool in name order, e.g. to export metrics or
+// clear all pools on low memory. fn may create or unregister named pools.
+func Each(fn func(name string, p *Pool)) {
+	named.mu.RLock()
+	names := make([]string, 0, len(named.pools))
+	for name := range named.pools {
+		names = append(names, name)
+	}
+	pools := make(map[string]*Pool, len(names))
+	for name, p := range named.pools {
+		pools[name] = p
+	}
+	named.mu.RUnlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		fn(name, pools[name])
+	}
+}
@@ -0,0 +1,74 @@
+package pool
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNamedPool tests registering, enumerating and unregistering named pools.
+func TestNamedPool(t *testing.T) {
+	a := NewNamedPool("test-a", func() interface{} { return new(int) })
+	b := NewNamedPool("test-b", func() interface{} { return new(string) })
+	defer Unregister("test-a")
+	defer Unregister("test-b")
+
+	if a.Name() != "test-a" {
+		t.Errorf("Expected name test-a, got %q", a.Name())
+	}
+	if p, ok := Lookup("test-b"); !ok || p != b {
+		t.Error("Expected to find test-b")
+	}
+
+	var names []string
+	Each(func(name string, p *Pool) {
+		if name == "test-a" || name == "test-b" {
+			names = append(names, name)
+		}
+	})
+	if len(names) != 2 || names[0] != "test-a" || names[1] != "test-b" {
+		t.Errorf("Expected test-a and test-b in order, got %v", names)
+	}
+
+	Unregister("test-a")
+	if _, ok := Lookup("test-a"); ok {
+		t.Error("Expected test-a to be unregistered")
+	}
+	if NewPool(func() interface{} { return 0 }).Name() != "" {
+		t.Error("Expected unnamed pools to have no name")
+	}
+}
+
+// logLines is an io.Writer passing every write to a channel, dropping it when full.
+type logLines chan string
+
+func (l logLines) Write(b []byte) (int, error) {
+	select {
+	case l <- string(b):
+	default:
+	}
+	return len(b), nil
+}
+
+// TestNamedPoolTasks tests that the background tasks of a named pool log its name.
+func TestNamedPoolTasks(t *testing.T) {
+	lines := make(logLines, 1)
+	p := NewNamedPool("test-tasks", func() interface{} {
+		return new(int)
+	}, WithLogger(slog.New(slog.NewTextHandler(lines, &slog.HandlerOptions{Level: slog.LevelDebug}))), WithRefresh(time.Millisecond, func(obj interface{}) error {
+		return errors.New("stale")
+	}))
+	defer Unregister("test-tasks")
+	predictable(p).Put(new(int))
+
+	select {
+	case line := <-lines:
+		if !strings.Contains(line, "pool=test-tasks") {
+			t.Errorf("Expected the log of the refresh to carry the name, got %q", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the failed refresh to be logged")
+	}
+}
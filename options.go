@@ -0,0 +1,444 @@
+package pool
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Option configures a Pool.
+type Option func(c *config)
+
+// WithLeakDetection enables checkout tracking.
+// Every Get records the caller's stack trace, and objects that are not Put back
+// within timeout are reported to the leak handler, which logs them by default.
+// Only objects of reference kinds (pointers, maps, channels, slices, funcs) can be tracked.
+func WithLeakDetection(timeout time.Duration) Option {
+	return func(c *config) {
+		c.leakTimeout = timeout
+	}
+}
+
+// WithLeakHandler sets the function receiving leak reports.
+func WithLeakHandler(fn func(Leak)) Option {
+	return func(c *config) {
+		if fn != nil {
+			c.leakHandler = fn
+		}
+	}
+}
+
+// WithDoublePutDetection enables tracking of the objects currently in the pool.
+// Putting an object that is already in the pool, without an intervening Get,
+// calls handler and drops the duplicate. If handler is nil, it panics instead.
+// Only objects of reference kinds (pointers, maps, channels, slices, funcs) can be tracked.
+func WithDoublePutDetection(handler func(obj interface{})) Option {
+	return func(c *config) {
+		if handler == nil {
+			handler = panicDoublePut
+		}
+		c.doublePutHandler = handler
+	}
+}
+
+// WithSanitizer enables the poisoning sanitizer.
+// Objects are overwritten with a poison pattern on Put and checked on Get;
+// if the pattern was modified, the object was used after it was returned and
+// handler is called (or, if handler is nil, Get panics). Objects are handed
+// out still poisoned, so callers relying on stale contents fail visibly.
+// Byte slices, *[]byte, *bytes.Buffer and Poisoner implementations are sanitized.
+// Building with the pool_sanitize tag enables the sanitizer for every pool.
+func WithSanitizer(handler func(obj interface{})) Option {
+	return func(c *config) {
+		if handler == nil {
+			handler = panicUseAfterPut
+		}
+		c.sanitizeHandler = handler
+	}
+}
+
+// WithLeaseFinalizer attaches a finalizer to every Lease.
+// A lease that is garbage collected without being released is reported to the
+// leak handler; if reclaim is true, its object is also Put back into the pool,
+// and otherwise it is Discarded, so it no longer counts against WithMaxInUse.
+// Only enable reclaim if callers never keep using an object after dropping its lease.
+func WithLeaseFinalizer(reclaim bool) Option {
+	return func(c *config) {
+		c.leaseFinalizer = true
+		c.leaseReclaim = reclaim
+	}
+}
+
+// WithLeaseReplace attaches a finalizer to every Lease like
+// WithLeaseFinalizer(false), and replaces the object of a dropped lease with a
+// new idle one from the factory, so a pool that was pre-warmed does not lose
+// capacity to callers that forget to release their leases.
+func WithLeaseReplace() Option {
+	return func(c *config) {
+		c.leaseFinalizer = true
+		c.leaseReclaim = false
+		c.leaseReplace = true
+	}
+}
+
+// WithFactoryContext sets a context-aware factory used instead of newFunc.
+// GetContext passes its context to fn, so expensive constructions (e.g. ones
+// performing I/O) can be canceled; Get and GetE pass context.Background().
+func WithFactoryContext(fn func(ctx context.Context) (interface{}, error)) Option {
+	return func(c *config) {
+		c.factoryCtx = fn
+	}
+}
+
+// WithCircuitBreaker stops calling a failing factory.
+// After failures consecutive factory errors, Gets that miss the pool fail fast
+// with ErrCircuitOpen for coolDown; then a single call probes the factory again.
+// Objects already in the pool are still handed out while the breaker is open.
+func WithCircuitBreaker(failures int, coolDown time.Duration) Option {
+	return func(c *config) {
+		c.breakerFailures = failures
+		c.breakerCoolDown = coolDown
+	}
+}
+
+// WithMaxInUse bounds the number of objects checked out at the same time.
+// Shard capacity only bounds idle objects; this bounds concurrent usage, and
+// requires every object retrieved to be returned with Put or Discard.
+// When n objects are in use, Get waits for one to be returned if wait is true
+// (GetContext gives up when its context is done), and fails with ErrExhausted
+// otherwise, in which case Get returns nil.
+func WithMaxInUse(n int, wait bool) Option {
+	return func(c *config) {
+		c.maxInUse = n
+		c.maxInUseWait = wait
+	}
+}
+
+// WithDestructor sets the function releasing an object that leaves the pool for
+// good, e.g. closing a file. It is called by Drain for idle objects and for
+// objects returned while the pool is draining or closed.
+func WithDestructor(fn func(obj interface{})) Option {
+	return func(c *config) {
+		c.destructor = fn
+	}
+}
+
+// WithObjectMetadata enables tracking of the creation time, last-used time and
+// use count of every object, reported by Inspect. Pool.Invalidate requires it.
+// Objects of non-reference kinds lose their metadata while checked out, and
+// metadata of checked-out objects is kept until they are Put or Discarded.
+func WithObjectMetadata() Option {
+	return func(c *config) {
+		c.metadata = true
+	}
+}
+
+// WithEvictionPolicy sets the policy choosing which idle objects Gets reuse and
+// which are evicted when a shard is full. The default is LIFOPolicy.
+func WithEvictionPolicy(policy EvictionPolicy) Option {
+	return func(c *config) {
+		c.policy = policy
+	}
+}
+
+// WithFIFO makes shards behave as queues instead of stacks: Get hands out the
+// least recently returned object, so all pooled objects get exercised roughly
+// equally. It is ignored if an eviction policy is set.
+func WithFIFO() Option {
+	return func(c *config) {
+		c.fifo = true
+	}
+}
+
+// Sizer reports the size of an object in bytes, e.g. the capacity of a buffer.
+type Sizer func(obj interface{}) int
+
+// WithSizer sets the function reporting the size of objects, see WithMaxMemory.
+func WithSizer(sizer Sizer) Option {
+	return func(c *config) {
+		c.sizer = sizer
+	}
+}
+
+// WithMaxMemory bounds the total size of the idle objects, as reported by the
+// Sizer, to bytes. Objects returned when the pool is at its limit are dropped.
+// It has no effect without WithSizer.
+func WithMaxMemory(bytes int64) Option {
+	return func(c *config) {
+		c.maxMemory = bytes
+	}
+}
+
+// WithGCTrim destroys the given fraction (0 to 1) of the idle objects after
+// each GC cycle, rounded down, starting with the least recently returned ones.
+// This gives sync.Pool-like cooperation with the GC while keeping part of the
+// pool warm.
+func WithGCTrim(fraction float64) Option {
+	return func(c *config) {
+		c.gcTrimFraction = fraction
+	}
+}
+
+// WithHooks sets functions called on pool lifecycle events, see Hooks.
+func WithHooks(hooks Hooks) Option {
+	return func(c *config) {
+		c.hooks = hooks
+	}
+}
+
+// WithProfileLabel runs the factory under the pprof label pool=name, so
+// profiles attribute the time and goroutines spent creating objects to the
+// pool instead of an anonymous closure. Note that heap profiles do not record
+// labels; they still point at the factory's allocation sites.
+func WithProfileLabel(name string) Option {
+	return func(c *config) {
+		c.profileLabel = name
+	}
+}
+
+// WithAutoTune periodically adjusts the capacity of each shard within
+// [cfg.MinCap, cfg.MaxCap] from the observed traffic: it grows while returned
+// objects are dropped and Gets miss, and shrinks while nothing misses and the
+// shards stay more than half full. The initial capacity is clamped to the bounds.
+func WithAutoTune(cfg AutoTuneConfig) Option {
+	return func(c *config) {
+		if cfg.MaxCap < cfg.MinCap {
+			cfg.MaxCap = cfg.MinCap
+		}
+		c.autoTune = &cfg
+	}
+}
+
+// WithAdaptiveSteal adapts the number of shards a Get searches when its
+// preferred shard is empty to how often stealing succeeds: the budget grows by
+// one shard after every successful steal and shrinks by one after every failed
+// search. At zero, Gets go straight to the factory, probing a single shard now
+// and then to notice when objects become available again.
+func WithAdaptiveSteal() Option {
+	return func(c *config) {
+		c.adaptiveSteal = true
+	}
+}
+
+// WithDeterministicSharding replaces the stack-address shard selection with a
+// sequence derived from seed and disables race chaos, so tests of code built on
+// the pool see reproducible shard placement and stats. The sequence is shared
+// by all goroutines behind a mutex, so it is meant for tests, not production.
+func WithDeterministicSharding(seed uint64) Option {
+	return func(c *config) {
+		c.shardSeed = &seed
+		c.raceChaos = false
+	}
+}
+
+// WithOverflow selects what happens to an object returned to a full shard:
+// OverflowDropNewest drops it, OverflowDropOldest evicts the least recently
+// returned object instead and OverflowSpill stores it in a neighboring shard.
+// An eviction policy set with WithEvictionPolicy takes precedence over
+// OverflowDropOldest.
+func WithOverflow(overflow Overflow) Option {
+	return func(c *config) {
+		c.overflow = overflow
+	}
+}
+
+// WithAffinity returns objects to the shard they were last taken from instead
+// of the caller's shard, so objects do not slowly migrate into a few shards
+// when producers and consumers run on different goroutines. It enables
+// WithObjectMetadata, which records the shard.
+func WithAffinity() Option {
+	return func(c *config) {
+		c.affinity = true
+		c.metadata = true
+	}
+}
+
+// WithHotCold puts a hot tier of up to hotCap objects in front of each shard.
+// Gets take the most recently returned hot object first and fall back to the
+// shard's cold objects, which are selected by the eviction policy as usual.
+// Returned objects always enter the hot tier; when it is full, or once an
+// object stayed hot for longer than age (one second if zero), it is demoted to
+// the cold tier, where the shard capacity and overflow behavior apply. A cold
+// object that is taken and returned again is thereby promoted. Each shard
+// holds up to hotCap objects on top of its capacity.
+func WithHotCold(hotCap int, age time.Duration) Option {
+	return func(c *config) {
+		c.hotCap = hotCap
+		c.hotAge = age
+	}
+}
+
+// WithZeroOnPut wipes byte slices, *[]byte and *bytes.Buffer objects before
+// they are retained, so contents such as credentials do not leak to the next
+// user of the object. Objects of other types are retained as they are; use
+// WithZeroFunc to wipe them.
+func WithZeroOnPut() Option {
+	return WithZeroFunc(zero)
+}
+
+// WithZeroFunc calls fn to wipe every object before it is retained, see
+// WithZeroOnPut.
+func WithZeroFunc(fn func(obj interface{})) Option {
+	return func(c *config) {
+		c.zeroFunc = fn
+	}
+}
+
+// WithClone makes the pool a pool of prototypes: Get takes an idle prototype,
+// or creates one with the factory, keeps it in the pool and returns
+// clone(prototype), so expensive construction such as parsing a template is
+// amortized while callers never share state. Put ends the checkout of a clone
+// without retaining it. Uses counted by WithObjectMetadata are clones made
+// from a prototype. The sanitizer and zeroing do not apply to prototypes.
+func WithClone(clone func(obj interface{}) interface{}) Option {
+	return func(c *config) {
+		c.clone = clone
+	}
+}
+
+// WithNewRateLimit limits the factory to r calls per second, so a miss storm
+// cannot overwhelm the systems an expensive factory dials. Gets that miss the
+// pool beyond the limit wait for their turn if wait is true (GetContext gives
+// up when its context is done or its deadline is too close), and fail with
+// ErrRateLimited otherwise. Objects already in the pool are handed out as usual.
+func WithNewRateLimit(r rate.Limit, wait bool) Option {
+	return func(c *config) {
+		c.newLimiter = rate.NewLimiter(r, 1)
+		c.newLimitWait = wait
+	}
+}
+
+// WithGroup makes the pool a member of g with the given weight, so its idle
+// objects count against the budget g shares among its members, see Group.
+// weight <= 0 means 1.
+func WithGroup(g *Group, weight int) Option {
+	return func(c *config) {
+		c.group = &groupMember{group: g, weight: int64(max(weight, 1))}
+	}
+}
+
+// WithShardCap sets the capacity of each shard, 128 by default.
+func WithShardCap(n int) Option {
+	return func(c *config) {
+		c.shardCap = n
+	}
+}
+
+// WithStealShardCount sets the max number of shards a Get steals from when its
+// own shard is empty, 4 by default.
+func WithStealShardCount(n int) Option {
+	return func(c *config) {
+		c.stealShardCnt = n
+	}
+}
+
+// WithArgsFactory sets the factory GetWith calls with its arguments on a miss,
+// e.g. to create a buffer pre-sized to a hint. The objects fn creates are
+// pooled with the others, so they must all be interchangeable. Get and GetWith
+// without this option use the pool's factory.
+func WithArgsFactory(fn func(args any) interface{}) Option {
+	return func(c *config) {
+		c.argsFactory = fn
+	}
+}
+
+// WithQuarantine holds returned objects aside for at least d before they
+// become idle and can be handed out again. A caller that keeps using an object
+// after Put then does not race with its next user right away, which mitigates
+// use-after-put bugs and, together with WithSanitizer, gives them time to be
+// caught. Quarantined objects count as retained but not as idle; the ones the
+// shards cannot take when their delay passed are destroyed.
+func WithQuarantine(d time.Duration) Option {
+	return func(c *config) {
+		c.quarantine = d
+	}
+}
+
+// WithLogger emits structured events to logger: drops of returned objects
+// while they last, factory errors, leak reports and evictions of idle objects,
+// at the levels set with WithLogLevels or DefaultLogLevels. Events of named
+// pools carry the pool's name.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// WithLogLevels sets the levels at which the events of WithLogger are logged.
+func WithLogLevels(levels LogLevels) Option {
+	return func(c *config) {
+		c.logLevels = &levels
+	}
+}
+
+// WithSlowHandler calls fn with the context of a Get whose wait for an in-use
+// slot took longer than wait, or whose factory call took longer than factory,
+// e.g. to record the starvation on the Get's trace span (see the otel
+// package). A zero threshold does not report the operation. fn runs on the
+// goroutine of the Get.
+func WithSlowHandler(wait, factory time.Duration, fn func(ctx context.Context, op SlowOp, d time.Duration)) Option {
+	return func(c *config) {
+		c.slow = &slowHandler{wait: wait, factory: factory, fn: fn}
+	}
+}
+
+// WithClock sets the source of time of the pool, so time-dependent features
+// such as object metadata, the hot tier, the quarantine, the circuit breaker
+// and the background tasks can be tested deterministically. Wait and latency
+// measurements and leak detection timers keep using the system time. A nil
+// clock keeps the system clock.
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithJitter randomizes the interval of each background task of the pool by
+// up to fraction (0 to 1) in either direction when the task starts, so the
+// maintenance of many pools created at the same time does not run in lockstep.
+func WithJitter(fraction float64) Option {
+	return func(c *config) {
+		c.jitter = min(max(fraction, 0), 1)
+	}
+}
+
+// WithSoftCap lets each shard accept up to extra objects above its capacity
+// instead of dropping them, in a soft tier whose objects are destroyed once
+// they stayed idle for ttl (one second if zero). This smooths bursty return
+// patterns, where objects dropped on Put would be needed again shortly after.
+// Gets take soft objects before the shard's other objects; the hot tier, if
+// set, still comes first.
+func WithSoftCap(extra int, ttl time.Duration) Option {
+	return func(c *config) {
+		c.softCap = extra
+		c.softTTL = ttl
+	}
+}
+
+// WithRetentionTarget makes the pool converge toward objects idle objects in
+// total: every second, half of the gap is closed by destroying the least
+// recently returned objects of every shard, in proportion to its size, or by
+// creating new ones with the factory. Unlike sync.Pool, whose contents go away
+// with GC cycles, the steady state is then independent of GOGC and of the
+// allocation rate. Shard capacities still bound the idle objects.
+func WithRetentionTarget(objects int) Option {
+	return func(c *config) {
+		c.retentionTarget = objects
+	}
+}
+
+// WithRefresh runs fn on every idle object once per interval, e.g. to ping a
+// connection or re-validate a token, and destroys the objects for which it
+// fails, so failures are found in the background rather than at Get time.
+// The objects are taken out one at a time while fn runs, so the others keep
+// serving Gets; an object is never refreshed while checked out.
+func WithRefresh(interval time.Duration, fn func(obj interface{}) error) Option {
+	return func(c *config) {
+		c.refreshInterval = interval
+		c.refresh = fn
+	}
+}
@@ -0,0 +1,97 @@
+// Package otel reports the stats of pools as OpenTelemetry metrics.
+package otel
+
+import (
+	"context"
+	"math"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/ongniud/pool"
+)
+
+// NameKey is the attribute holding the name of a pool.
+const NameKey = attribute.Key("pool.name")
+
+// BoundKey is the attribute holding the upper bound, in seconds, of a bucket
+// of the pool.waits histogram; the last bucket is +Inf.
+const BoundKey = attribute.Key("le")
+
+// Register registers asynchronous instruments that report the stats of the
+// named pools against meter:
+//   - pool.idle: idle objects held by the pool
+//   - pool.in_use: objects checked out and not yet returned
+//   - pool.gets: Gets served by the pool
+//   - pool.misses: Gets that had to create a new object
+//   - pool.wait_time: total time Gets spent waiting for an in-use slot, in seconds
+//   - pool.waits: Gets per bucket of the wait-time histogram, cumulative
+//     like Prometheus buckets and labeled with BoundKey
+//
+// Each observation carries the pool's name as NameKey. Unregister the
+// returned registration to stop reporting.
+func Register(meter metric.Meter, pools map[string]*pool.Pool) (metric.Registration, error) {
+	idle, err := meter.Int64ObservableGauge("pool.idle",
+		metric.WithDescription("Idle objects held by the pool"), metric.WithUnit("{object}"))
+	if err != nil {
+		return nil, err
+	}
+	inUse, err := meter.Int64ObservableUpDownCounter("pool.in_use",
+		metric.WithDescription("Objects checked out and not yet returned"), metric.WithUnit("{object}"))
+	if err != nil {
+		return nil, err
+	}
+	gets, err := meter.Int64ObservableCounter("pool.gets",
+		metric.WithDescription("Gets served by the pool"), metric.WithUnit("{get}"))
+	if err != nil {
+		return nil, err
+	}
+	misses, err := meter.Int64ObservableCounter("pool.misses",
+		metric.WithDescription("Gets that had to create a new object"), metric.WithUnit("{get}"))
+	if err != nil {
+		return nil, err
+	}
+	waitTime, err := meter.Float64ObservableCounter("pool.wait_time",
+		metric.WithDescription("Total time Gets spent waiting for an in-use slot"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	waits, err := meter.Int64ObservableCounter("pool.waits",
+		metric.WithDescription("Gets per bucket of the wait-time histogram"), metric.WithUnit("{get}"))
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string]metric.MeasurementOption, len(pools))
+	buckets := make(map[string][]metric.MeasurementOption, len(pools))
+	for name, p := range pools {
+		attrs[name] = metric.WithAttributes(NameKey.String(name))
+		bounds := p.Stats().Waits.Bounds
+		for i := 0; i <= len(bounds); i++ {
+			le := math.Inf(1)
+			if i < len(bounds) {
+				le = bounds[i].Seconds()
+			}
+			buckets[name] = append(buckets[name],
+				metric.WithAttributes(NameKey.String(name), BoundKey.Float64(le)))
+		}
+	}
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for name, p := range pools {
+			st := p.Stats()
+			attr := attrs[name]
+			o.ObserveInt64(idle, int64(st.Idle), attr)
+			o.ObserveInt64(inUse, st.InUse, attr)
+			o.ObserveInt64(gets, int64(st.Gets), attr)
+			o.ObserveInt64(misses, int64(st.Misses), attr)
+			o.ObserveFloat64(waitTime, st.WaitTime.Seconds(), attr)
+			var cumulative uint64
+			for i, n := range st.Waits.Counts {
+				cumulative += n
+				o.ObserveInt64(waits, int64(cumulative), buckets[name][i])
+			}
+		}
+		return nil
+	}, idle, inUse, gets, misses, waitTime, waits)
+}
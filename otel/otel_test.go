@@ -0,0 +1,50 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/ongniud/pool"
+)
+
+// TestRegister tests that the stats of a pool are reported with its name.
+func TestRegister(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	p := pool.NewPool(func() interface{} {
+		return new(int)
+	})
+	p.Get()
+	p.Get()
+
+	reg, err := Register(meter, map[string]*pool.Pool{"ints": p})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer reg.Unregister()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range sum.DataPoints {
+					if name, _ := dp.Attributes.Value(NameKey); name.AsString() != "ints" {
+						t.Errorf("Expected attribute %s=ints, got %q", NameKey, name.AsString())
+					}
+					values[m.Name] = dp.Value
+				}
+			}
+		}
+	}
+	if values["pool.gets"] != 2 || values["pool.misses"] != 2 || values["pool.in_use"] != 2 {
+		t.Errorf("Expected 2 gets, misses and in use, got %v", values)
+	}
+}
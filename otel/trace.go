@@ -0,0 +1,36 @@
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ongniud/pool"
+)
+
+// DurationKey is the attribute holding the duration, in seconds, of a slow
+// operation recorded by WithTracing.
+const DurationKey = attribute.Key("pool.duration")
+
+// WithTracing records an event on the active span of the context of a Get
+// whose wait for an in-use slot took longer than wait (pool.slow_wait), or
+// whose factory call took longer than factory (pool.slow_factory), so pool
+// starvation shows up in distributed traces. The events carry the duration as
+// DurationKey, and the pool name as NameKey if name is not empty. A zero
+// threshold does not record the operation; Gets without a context, such as
+// Get and GetE, have no span to record on.
+func WithTracing(name string, wait, factory time.Duration) pool.Option {
+	return pool.WithSlowHandler(wait, factory, func(ctx context.Context, op pool.SlowOp, d time.Duration) {
+		span := trace.SpanFromContext(ctx)
+		if !span.IsRecording() {
+			return
+		}
+		attrs := []attribute.KeyValue{DurationKey.Float64(d.Seconds())}
+		if name != "" {
+			attrs = append(attrs, NameKey.String(name))
+		}
+		span.AddEvent("pool.slow_"+op.String(), trace.WithAttributes(attrs...))
+	})
+}
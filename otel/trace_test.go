@@ -0,0 +1,46 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ongniud/pool"
+)
+
+// TestWithTracing tests that slow factory calls are recorded on the active span.
+func TestWithTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	p := pool.NewPool(func() interface{} {
+		time.Sleep(5 * time.Millisecond)
+		return new(int)
+	}, WithTracing("ints", 0, time.Millisecond))
+	ctx, span := tracer.Start(context.Background(), "get")
+	if _, err := p.GetContext(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || len(spans[0].Events()) != 1 {
+		t.Fatalf("Expected 1 span with 1 event, got %v", spans)
+	}
+	ev := spans[0].Events()[0]
+	if ev.Name != "pool.slow_factory" {
+		t.Errorf("Expected a pool.slow_factory event, got %q", ev.Name)
+	}
+	found := false
+	for _, kv := range ev.Attributes {
+		if kv.Key == NameKey && kv.Value.AsString() == "ints" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected attribute %s=ints, got %v", NameKey, ev.Attributes)
+	}
+}
@@ -0,0 +1,39 @@
+package pool
+
+import "sync/atomic"
+
+// Overflow selects what happens to an object returned to a full shard.
+type Overflow int
+
+const (
+	// OverflowDropNewest drops the returned object (default)
+	OverflowDropNewest Overflow = iota
+	// OverflowDropOldest destroys the least recently returned object of the
+	// shard to make room, keeping the hottest objects pooled
+	OverflowDropOldest
+	// OverflowSpill stores the returned object in one of the next shards with
+	// room, the same ones a Get steals from, and drops it only if they are
+	// all full
+	OverflowSpill
+)
+
+// pushPolicy returns the policy selecting the victims of a full shard under cfg.
+func pushPolicy(cfg *config) EvictionPolicy {
+	if cfg.policy == nil && cfg.overflow == OverflowDropOldest {
+		return LRUPolicy
+	}
+	return cfg.policy
+}
+
+// spill stores e in one of the stealShardCnt shards after shardID with room
+// and reports whether it did.
+func (p *Pool) spill(e entry, shardID uint64, cfg *config) bool {
+	for i := 0; i < cfg.stealShardCnt; i++ {
+		shardID = (shardID + 1) & p.shardMask
+		if ok, _ := p.shards[shardID].push(e, p.shards[shardID].limit(cfg), nil); ok {
+			atomic.AddUint64(&p.shards[shardID].puts, 1)
+			return true
+		}
+	}
+	return false
+}
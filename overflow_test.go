@@ -0,0 +1,74 @@
+package pool
+
+import "testing"
+
+// fullPool returns a pool whose shards hold one object each, all of them full.
+func fullPool(opts ...Option) *Pool {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, opts...))
+	p.updateConfig(func(c *config) {
+		c.shardCap = 1
+	})
+	for i := range p.shards {
+		p.shards[i].push(entry{obj: new(int)}, 1, nil)
+	}
+	return p
+}
+
+// TestOverflowDropNewest tests that a full shard drops the returned object by default.
+func TestOverflowDropNewest(t *testing.T) {
+	p := fullPool()
+	obj := new(int)
+	if p.PutCheck(obj) {
+		t.Error("Expected the returned object to be dropped")
+	}
+}
+
+// TestOverflowDropOldest tests that a full shard evicts its oldest object for the returned one.
+func TestOverflowDropOldest(t *testing.T) {
+	destroyed := 0
+	p := fullPool(WithOverflow(OverflowDropOldest), WithDestructor(func(obj interface{}) {
+		destroyed++
+	}))
+	obj := new(int)
+	if !p.PutCheck(obj) {
+		t.Error("Expected the returned object to be retained")
+	}
+	if destroyed != 1 || idleCount(p) != len(p.shards) {
+		t.Errorf("Expected 1 evicted object and full shards, got %d and %d idle", destroyed, idleCount(p))
+	}
+	found := false
+	p.Range(func(o interface{}) bool {
+		found = found || o == obj
+		return !found
+	})
+	if !found {
+		t.Error("Expected the returned object to be pooled")
+	}
+}
+
+// TestOverflowSpill tests that a full shard spills the returned object to a neighbor with room.
+func TestOverflowSpill(t *testing.T) {
+	p := fullPool(WithOverflow(OverflowSpill))
+	if p.PutCheck(new(int)) {
+		t.Error("Expected the object to be dropped when every shard is full")
+	}
+
+	// Puts from the same function prefer the same shard; empty its neighbor
+	preferred := 0
+	for i, sh := range p.Stats().Shards {
+		if sh.Puts > 0 {
+			preferred = i
+		}
+	}
+	next := (preferred + 1) & int(p.shardMask)
+	p.takeIdle(next)
+
+	if !p.PutCheck(new(int)) {
+		t.Error("Expected the object to spill into the neighboring shard")
+	}
+	if st := p.Stats(); st.Shards[next].Idle != 1 || st.Shards[next].Puts != 1 {
+		t.Errorf("Expected the neighbor to hold the spilled object, got %+v", st.Shards[next])
+	}
+}
@@ -0,0 +1,75 @@
+package pool
+
+// IdleList is a read-only view of the idle objects of a shard,
+// in the order they were returned to the pool.
+type IdleList interface {
+	// Len returns the number of idle objects.
+	Len() int
+	// At returns the object at index i, without its shard.
+	At(i int) ObjectInfo
+}
+
+// EvictionPolicy decides which idle objects of a shard are reused and evicted.
+// Its methods are called with the shard locked, so they must be fast, must not
+// panic and must not call back into the pool. A policy may be shared by all shards of a pool
+// and must then be safe for concurrent use.
+type EvictionPolicy interface {
+	// OnPut is called after an object was added to a shard.
+	OnPut(info ObjectInfo)
+	// OnGet returns the index of the idle object handed out by a Get.
+	// idle is never empty.
+	OnGet(idle IdleList) int
+	// SelectVictims returns the indexes of up to n idle objects to evict to make
+	// room for an object returned to a full shard. Returning fewer indexes
+	// drops the returned object instead. Evicted objects are destroyed.
+	SelectVictims(idle IdleList, n int) []int
+}
+
+var (
+	// LIFOPolicy reuses the most recently returned object and drops objects
+	// returned to a full shard. It is the default.
+	LIFOPolicy EvictionPolicy = lifoPolicy{}
+	// FIFOPolicy reuses the least recently returned object, so all pooled
+	// objects get exercised roughly equally, and drops objects returned to a full shard.
+	FIFOPolicy EvictionPolicy = fifoPolicy{}
+	// LRUPolicy reuses the most recently returned object and, when a shard is
+	// full, evicts the least recently returned ones in favor of the new object.
+	LRUPolicy EvictionPolicy = lruPolicy{}
+)
+
+// idleList implements IdleList over the entries of a shard.
+type idleList []entry
+
+func (l idleList) Len() int            { return len(l) }
+func (l idleList) At(i int) ObjectInfo { return l[i].info() }
+
+// lifoPolicy implements LIFOPolicy.
+type lifoPolicy struct{}
+
+func (lifoPolicy) OnPut(info ObjectInfo)                    {}
+func (lifoPolicy) OnGet(idle IdleList) int                  { return idle.Len() - 1 }
+func (lifoPolicy) SelectVictims(idle IdleList, n int) []int { return nil }
+
+// fifoPolicy implements FIFOPolicy.
+type fifoPolicy struct{}
+
+func (fifoPolicy) OnPut(info ObjectInfo)                    {}
+func (fifoPolicy) OnGet(idle IdleList) int                  { return 0 }
+func (fifoPolicy) SelectVictims(idle IdleList, n int) []int { return nil }
+
+// lruPolicy implements LRUPolicy.
+type lruPolicy struct{}
+
+func (lruPolicy) OnPut(info ObjectInfo)   {}
+func (lruPolicy) OnGet(idle IdleList) int { return idle.Len() - 1 }
+
+func (lruPolicy) SelectVictims(idle IdleList, n int) []int {
+	if n > idle.Len() {
+		n = idle.Len()
+	}
+	victims := make([]int, n)
+	for i := range victims {
+		victims[i] = i
+	}
+	return victims
+}
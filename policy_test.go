@@ -0,0 +1,95 @@
+package pool
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// TestFIFOPolicy tests that FIFOPolicy reuses the oldest object first.
+func TestFIFOPolicy(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithEvictionPolicy(FIFOPolicy)))
+
+	objs := []*int{new(int), new(int), new(int)}
+	for _, obj := range objs {
+		p.Put(obj)
+	}
+	for i, obj := range objs {
+		if got := p.Get(); got != obj {
+			t.Errorf("Expected object %d in FIFO order", i)
+		}
+	}
+}
+
+// TestFIFO tests that WithFIFO makes shards hand out objects in FIFO order.
+func TestFIFO(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithFIFO()))
+
+	objs := []*int{new(int), new(int), new(int)}
+	for _, obj := range objs[:2] {
+		p.Put(obj)
+	}
+	if got := p.Get(); got != objs[0] {
+		t.Error("Expected the first object to be handed out first")
+	}
+	p.Put(objs[2])
+	for i, obj := range objs[1:] {
+		if got := p.Get(); got != obj {
+			t.Errorf("Expected object %d in FIFO order", i+1)
+		}
+	}
+	if !p.View().Config.FIFO {
+		t.Error("Expected the view to report FIFO mode")
+	}
+}
+
+// TestLRUPolicy tests that LRUPolicy evicts the least recently returned object.
+func TestLRUPolicy(t *testing.T) {
+	var destroyed []interface{}
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithEvictionPolicy(LRUPolicy), WithDestructor(func(obj interface{}) {
+		destroyed = append(destroyed, obj)
+	})))
+	p.updateConfig(func(c *config) {
+		c.shardCap = 2
+	})
+
+	objs := []*int{new(int), new(int), new(int)}
+	for _, obj := range objs {
+		p.Put(obj)
+	}
+	if len(destroyed) != 1 || destroyed[0] != objs[0] {
+		t.Errorf("Expected the oldest object to be evicted, got %v", destroyed)
+	}
+	if got := p.Get(); got != objs[2] {
+		t.Error("Expected the most recently returned object to be reused")
+	}
+}
+
+// countingPolicy is a LIFO policy counting the objects added.
+type countingPolicy struct {
+	lifoPolicy
+	puts int32
+}
+
+func (c *countingPolicy) OnPut(info ObjectInfo) {
+	atomic.AddInt32(&c.puts, 1)
+}
+
+// TestCustomPolicy tests that a custom policy is notified of Puts.
+func TestCustomPolicy(t *testing.T) {
+	policy := &countingPolicy{}
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithEvictionPolicy(policy)))
+
+	p.Put(new(int))
+	p.Put(new(int))
+	if n := atomic.LoadInt32(&policy.puts); n != 2 {
+		t.Errorf("Expected 2 OnPut calls, got %d", n)
+	}
+}
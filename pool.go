@@ -1,17 +1,25 @@
 package pool
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/bits"
+	"runtime/pprof"
+	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
 const (
-	// Maximum number of shards to steal from when the preferred shard is empty
+	// Default maximum number of shards to steal from when the preferred shard is empty
 	stealShardCnt = 4
 	// Count of shard
 	shardCount = 16
-	// Capacity of each shard
+	// Default capacity of each shard
 	shardCap = 128
 )
 
@@ -19,57 +27,688 @@ const (
 type Pool struct {
 	shards    []poolShard
 	shardMask uint64
-	newFunc   func() interface{}
+	newFunc   func() (interface{}, error)
 	tick      uint64
+	cfg       atomic.Pointer[config]
+	leaks     leakTracker
+	weights   weightTracker
+	idle      idleTracker
+	breaker   circuitBreaker
+	inUse     semaphore
+	state     int32
+	meta      metaTracker
+	retained  int64
+	pressure  int32
+	gets      uint64
+	puts      uint64
+	misses    uint64
+	drops     uint64
+	waits     histogram
+	name      string
+	steal     stealBudget
+	epoch     uint64
+	sequence  *shardSequence
+	tasks     uint32
+	// Bit i is set while shard i holds idle objects
+	nonEmpty uint64
+	// 1 while the pool is frozen, see Freeze
+	frozen int32
+	// Returned objects waiting to become idle, see WithQuarantine
+	quarantine quarantine
+	// Time of the last drop report and number of drops reported, see WithLogger
+	dropLogAt   int64
+	dropsLogged uint64
 }
 
 // NewPool creates a new object pool.
 // fn is the function used to create a new object when the pool is empty.
-func NewPool(fn func() interface{}) *Pool {
+// opts customize the pool, see the With* functions.
+func NewPool(fn func() interface{}, opts ...Option) *Pool {
 	if fn == nil {
 		panic("newFunc cannot be nil")
 	}
+	return newPool("", func() (interface{}, error) {
+		return fn(), nil
+	}, opts)
+}
+
+// NewPoolE creates a new object pool whose factory can fail.
+// fn is the function used to create a new object when the pool is empty;
+// its errors are returned by GetE.
+func NewPoolE(fn func() (interface{}, error), opts ...Option) *Pool {
+	if fn == nil {
+		panic("newFunc cannot be nil")
+	}
+	return newPool("", fn, opts)
+}
+
+// newPool creates a new object pool with the name and the factory fn. The name
+// is set before the background tasks start, since they log it.
+func newPool(name string, fn func() (interface{}, error), opts []Option) *Pool {
 	p := &Pool{
+		name:      name,
 		shards:    make([]poolShard, shardCount),
 		shardMask: uint64(shardCount - 1),
 		newFunc:   fn,
 	}
+	for i := range p.shards {
+		p.shards[i].bit = 1 << i
+		p.shards[i].nonEmpty = &p.nonEmpty
+	}
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.autoTune != nil {
+		cfg.shardCap = min(max(cfg.shardCap, cfg.autoTune.MinCap), cfg.autoTune.MaxCap)
+	}
+	if cfg.shardSeed != nil {
+		p.sequence = newShardSequence(*cfg.shardSeed)
+	}
+	if cfg.group != nil {
+		cfg.group.join(p)
+	}
+	p.cfg.Store(cfg)
+	p.inUse.resize(int64(cfg.maxInUse))
+	p.steal.n = int32(cfg.stealShardCnt)
+	p.startTasks(cfg)
 	return p
 }
 
 // Get retrieves an object from the pool.
 // 1. Try to get an object from the preferred shard.
-// 2. If the preferred shard is empty, try to steal from other shards (up to 4 shards).
+// 2. If the preferred shard is empty, try to steal from other shards (up to stealShardCnt shards).
 // 3. If all shards are empty, create a new object using the newFunc.
+// If the factory of a pool created with NewPoolE fails, Get returns nil; use GetE to get the error.
 func (p *Pool) Get() interface{} {
+	obj, _ := p.GetE()
+	return obj
+}
+
+// GetE retrieves an object from the pool like Get, but returns the error of
+// the factory if a new object could not be created.
+func (p *Pool) GetE() (interface{}, error) {
+	return p.GetContext(context.Background())
+}
+
+// GetContext retrieves an object from the pool like GetE.
+// If the pool was created WithFactoryContext, ctx is passed to the factory so
+// an in-flight construction can be canceled.
+func (p *Pool) GetContext(ctx context.Context) (interface{}, error) {
+	return p.getContext(ctx, getOptions{})
+}
+
+// GetOr retrieves an object from the pool like Get, but creates it with fn
+// instead of the pool's factory if no idle object is available. The objects fn
+// creates must be interchangeable with the pool's, since they are pooled and
+// handed out to any caller once they are Put back.
+func (p *Pool) GetOr(fn func() interface{}) interface{} {
+	obj, _ := p.getContext(context.Background(), getOptions{
+		factory: func(context.Context) (interface{}, error) {
+			return fn(), nil
+		},
+	})
+	return obj
+}
+
+// GetWith retrieves an object from the pool like Get, but passes args to the
+// factory set with WithArgsFactory if no idle object is available.
+func (p *Pool) GetWith(args any) interface{} {
+	var o getOptions
+	if fn := p.config().argsFactory; fn != nil {
+		o.factory = func(context.Context) (interface{}, error) {
+			return fn(args), nil
+		}
+	}
+	obj, _ := p.getContext(context.Background(), o)
+	return obj
+}
+
+// GetTimeout retrieves an object from the pool like GetE, but gives up with
+// ErrTimeout after d if the pool is at its in-use limit and waits, see
+// WithMaxInUse.
+func (p *Pool) GetTimeout(d time.Duration) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return p.GetContext(ctx)
+}
+
+// getOptions customize a single Get.
+type getOptions struct {
+	// Shard to start at, see GetHint
+	hint shardHint
+	// Factory used instead of the pool's on a miss, nil for the pool's
+	factory func(ctx context.Context) (interface{}, error)
+	// The caller already holds an in-use slot, see Semaphore.Get
+	acquired bool
+	// In-use units taken by the object, see GetWeighted; 0 means 1
+	weight int64
+}
+
+// getContext retrieves an object from the pool like GetContext, customized by o.
+func (p *Pool) getContext(ctx context.Context, o getOptions) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if atomic.LoadInt32(&p.state) != stateOpen {
+		return nil, ErrPoolClosed
+	}
+	cfg := p.config()
+	weight := max(o.weight, 1)
+	if cfg.maxInUse > 0 && !o.acquired {
+		if err := p.acquire(ctx, cfg, weight); err != nil {
+			return nil, err
+		}
+	}
+	obj, err := p.get(ctx, cfg, o)
+	if err != nil {
+		if cfg.maxInUse > 0 && !o.acquired {
+			p.inUse.release(weight)
+		}
+		return nil, err
+	}
+	if cfg.clone != nil {
+		obj = p.cloned(obj, cfg)
+	}
+	p.checkout(obj, cfg)
+	if cfg.maxInUse > 0 && weight > 1 {
+		p.weigh(obj, weight)
+	}
+	return obj, nil
+}
+
+// TryGet retrieves an idle object from the pool like Get, but never creates
+// one: it reports false if no idle object is found or the pool is at its
+// in-use limit or closed.
+func (p *Pool) TryGet() (interface{}, bool) {
+	if atomic.LoadInt32(&p.state) != stateOpen {
+		return nil, false
+	}
+	cfg := p.config()
+	if cfg.maxInUse > 0 && !p.inUse.tryAcquire(1) {
+		return nil, false
+	}
+	obj, ok := p.take(cfg, shardHint{})
+	if !ok {
+		if cfg.maxInUse > 0 {
+			p.inUse.release(1)
+		}
+		return nil, false
+	}
+	atomic.AddUint64(&p.gets, 1)
+	if cfg.clone != nil {
+		obj = p.cloned(obj, cfg)
+	}
+	p.checkout(obj, cfg)
+	return obj, true
+}
+
+// checkout starts the checkout of an object handed out by a Get.
+func (p *Pool) checkout(obj interface{}, cfg *config) {
+	atomic.AddInt64(&p.shards[p.shardID()].checkedOut, 1)
+	if cfg.leakTimeout > 0 {
+		p.leaks.checkout(obj, cfg.leakTimeout, p.reportLeak)
+	}
+	if cfg.hooks.OnGet != nil {
+		cfg.hooks.OnGet(obj)
+	}
+}
+
+// acquire reserves n in-use units, waiting for them if cfg says so.
+func (p *Pool) acquire(ctx context.Context, cfg *config, n int64) error {
+	if cfg.maxInUseWait {
+		if p.inUse.tryAcquire(n) {
+			p.waits.record(0)
+			return nil
+		}
+		start := time.Now()
+		err := p.inUse.acquire(ctx, n)
+		d := time.Since(start)
+		p.waits.record(d)
+		cfg.slow.observe(ctx, SlowWait, d)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %w", ErrTimeout, err)
+		}
+		return err
+	}
+	if !p.inUse.tryAcquire(n) {
+		return ErrExhausted
+	}
+	return nil
+}
+
+// get retrieves an object from the shards, starting at the shard of the hint
+// of o if it is set, or creates a new one.
+func (p *Pool) get(ctx context.Context, cfg *config, o getOptions) (interface{}, error) {
+	atomic.AddUint64(&p.gets, 1)
+	// A frozen pool cannot create the object of a chaos miss
+	if cfg.raceChaos && !p.Frozen() && raceDrop() {
+		return p.miss(ctx, cfg, o.factory)
+	}
+	if obj, ok := p.take(cfg, o.hint); ok {
+		return obj, nil
+	}
+
+	// 3. All shards are empty, create a new object
+	return p.miss(ctx, cfg, o.factory)
+}
+
+// take removes an idle object from the shards, starting at the shard of hint
+// if it is set, and reports whether there was one.
+func (p *Pool) take(cfg *config, hint shardHint) (interface{}, bool) {
 	// 1. Try to get an object from the preferred shard
-	shardID := p.shardID()
+	shardID := p.hintedShard(hint, cfg)
 	shard := &p.shards[shardID]
-	if obj := shard.pop(); obj != nil {
-		return obj
+	atomic.AddUint64(&shard.gets, 1)
+	if !shard.empty() {
+		if e, ok := p.pop(shard, cfg); ok {
+			atomic.AddUint64(&shard.hits, 1)
+			return p.popped(e, shardID, cfg), true
+		}
 	}
 
-	// 2. Try to steal from other shards, up to 4 shards
-	for i := 0; i < stealShardCnt; i++ {
-		shardID = (shardID + 1) & p.shardMask
+	// 2. Try to steal from the non-empty ones of the next stealShardCnt shards,
+	// found in the bitmap without probing the empty ones
+	home := shardID
+	for candidates := p.stealCandidates(home, p.stealBudget(cfg)); candidates != 0; candidates &= candidates - 1 {
+		shardID = (home + 1 + uint64(bits.TrailingZeros64(candidates))) & p.shardMask
 		shard = &p.shards[shardID]
-		if obj := shard.pop(); obj != nil {
-			return obj
+		if e, ok := p.pop(shard, cfg); ok {
+			atomic.AddUint64(&shard.steals, 1)
+			if cfg.hooks.OnSteal != nil {
+				cfg.hooks.OnSteal(e.obj, int(shardID))
+			}
+			if cfg.adaptiveSteal {
+				p.stealDone(true)
+			}
+			return p.popped(e, shardID, cfg), true
 		}
 	}
+	if cfg.adaptiveSteal {
+		p.stealDone(false)
+	}
+	return nil, false
+}
 
-	// 3. All shards are empty, create a new object
+// stealCandidates returns the non-empty shards among the n shards after
+// shardID as a bitmap, bit i standing for shard shardID+1+i.
+func (p *Pool) stealCandidates(shardID uint64, n int) uint64 {
+	count := uint64(len(p.shards))
+	if n <= 0 {
+		return 0
+	}
+	all := atomic.LoadUint64(&p.nonEmpty)
+	shift := (shardID + 1) & p.shardMask
+	rotated := all >> shift
+	if shift > 0 {
+		rotated |= all << (count - shift)
+	}
+	if uint64(n) < count {
+		rotated &= 1<<uint(n) - 1
+	} else if count < 64 {
+		rotated &= 1<<count - 1
+	}
+	return rotated
+}
+
+// miss creates a new object for a Get that found no idle one.
+// factory is the per-call factory overriding the pool's, or nil.
+func (p *Pool) miss(ctx context.Context, cfg *config, factory func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	atomic.AddUint64(&p.misses, 1)
+	if cfg.hooks.OnMiss != nil {
+		cfg.hooks.OnMiss()
+	}
+	if atomic.LoadInt32(&p.frozen) == 1 {
+		return nil, ErrFrozen
+	}
+	return p.newObject(ctx, cfg, factory)
+}
+
+// newObject creates a new object with factory if set, or with the pool's,
+// going through the circuit breaker if configured.
+func (p *Pool) newObject(ctx context.Context, cfg *config, factory func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if cfg.newLimiter != nil {
+		if err := p.limitNew(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+	var obj interface{}
+	var err error
+	var start time.Time
+	if cfg.slow.threshold(SlowFactory) > 0 {
+		start = time.Now()
+	}
+	if cfg.breakerFailures > 0 {
+		obj, err = p.newObjectBreaker(ctx, cfg, factory)
+	} else {
+		obj, err = p.callFactory(ctx, cfg, factory)
+	}
+	if !start.IsZero() {
+		cfg.slow.observe(ctx, SlowFactory, time.Since(start))
+	}
+	if err != nil {
+		p.log(cfg, cfg.levels().FactoryErrors, "pool factory failed", slog.Any("error", err))
+	}
+	if err == nil && cfg.metadata {
+		now := cfg.clock.Now()
+		p.meta.checkout(obj, &objectMeta{created: now, lastUsed: now, epoch: atomic.LoadUint64(&p.epoch)})
+	}
+	return obj, err
+}
+
+// limitNew waits for the rate limiter of cfg to allow a factory call, or
+// fails with ErrRateLimited if cfg does not wait.
+func (p *Pool) limitNew(ctx context.Context, cfg *config) error {
+	if !cfg.newLimitWait {
+		if !cfg.newLimiter.Allow() {
+			return ErrRateLimited
+		}
+		return nil
+	}
+	return cfg.newLimiter.Wait(ctx)
+}
+
+// callFactory creates a new object, under the pprof label of cfg if set.
+func (p *Pool) callFactory(ctx context.Context, cfg *config, factory func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if cfg.profileLabel != "" {
+		var obj interface{}
+		var err error
+		pprof.Do(ctx, pprof.Labels("pool", cfg.profileLabel), func(ctx context.Context) {
+			obj, err = p.runFactory(ctx, cfg, factory)
+		})
+		return obj, err
+	}
+	return p.runFactory(ctx, cfg, factory)
+}
+
+// runFactory creates a new object with factory if set, the context factory if
+// configured, or with newFunc.
+func (p *Pool) runFactory(ctx context.Context, cfg *config, factory func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if factory != nil {
+		return factory(ctx)
+	}
+	if cfg.factoryCtx != nil {
+		return cfg.factoryCtx(ctx)
+	}
 	return p.newFunc()
 }
 
+// poison overwrites the object of e with the poison pattern if the sanitizer
+// is enabled and e was not poisoned yet. Clone prototypes are never poisoned.
+func (p *Pool) poison(e *entry, cfg *config) {
+	if cfg.sanitizeHandler != nil && cfg.clone == nil && !e.poisoned {
+		poison(e.obj)
+		e.poisoned = true
+	}
+}
+
+// popped does the bookkeeping for an entry that was removed from shard shardID and returns its object.
+func (p *Pool) popped(e entry, shardID uint64, cfg *config) interface{} {
+	p.removed(e)
+	if e.meta != nil {
+		e.meta.home = shardHint{key: shardID, set: true}
+		p.meta.checkout(e.obj, e.meta)
+	}
+	if cfg.sanitizeHandler != nil && cfg.clone == nil && !poisoned(e.obj) {
+		cfg.sanitizeHandler(e.obj)
+	}
+	return e.obj
+}
+
 // Put returns an object to the pool.
 // If the object is nil, it will be ignored.
 func (p *Pool) Put(obj interface{}) {
+	p.PutCheck(obj)
+}
+
+// PutCheck returns an object to the pool like Put and reports whether the pool
+// retained it. An object that is not retained, e.g. because its shard is full or
+// the pool is closed, is no longer tracked by the pool, so the caller can close
+// or free it right away.
+func (p *Pool) PutCheck(obj interface{}) bool {
+	return p.putHooked(obj, putOptions{})
+}
+
+// PutE returns an object to the pool like Put, but fails with ErrPoolClosed if
+// the pool is draining or closed, in which case the object was destroyed.
+func (p *Pool) PutE(obj interface{}) error {
+	if !p.PutCheck(obj) && obj != nil && atomic.LoadInt32(&p.state) != stateOpen {
+		return ErrPoolClosed
+	}
+	return nil
+}
+
+// putOptions tag an object returned to the pool.
+type putOptions struct {
+	// Priority class, see PutPriority
+	priority int
+	// Shard to return the object to, see PutHint
+	hint shardHint
+}
+
+// putHooked returns an object to the pool, calling the hooks, and reports
+// whether it was retained.
+func (p *Pool) putHooked(obj interface{}, o putOptions) bool {
+	if obj == nil {
+		return false
+	}
+	hooks := p.config().hooks
+	if hooks.OnPut != nil {
+		hooks.OnPut(obj)
+	}
+	ok := p.putWith(obj, o)
+	if !ok && hooks.OnDiscard != nil {
+		hooks.OnDiscard(obj)
+	}
+	return ok
+}
+
+// Discard reports that an object retrieved from the pool will not be returned,
+// e.g. because it was closed after an error. It frees the object's in-use slot
+// and stops tracking it; the object itself is not retained.
+func (p *Pool) Discard(obj interface{}) {
 	if obj == nil {
 		return
 	}
-	shardID := p.shardID()
-	p.shards[shardID].push(obj)
+	cfg := p.config()
+	p.checkin(obj, cfg)
+	if cfg.hooks.OnDiscard != nil {
+		cfg.hooks.OnDiscard(obj)
+	}
+}
+
+// checkin ends the checkout of an object that is being returned or discarded.
+// It returns the object's metadata if metadata tracking is enabled.
+func (p *Pool) checkin(obj interface{}, cfg *config) *objectMeta {
+	counted := p.uncount(p.shardID())
+	if p.leaks.tracking() {
+		p.leaks.checkin(obj)
+	}
+	if cfg.maxInUse > 0 {
+		// Objects the pool never handed out hold no in-use units
+		if weight := p.weights.checkin(obj); counted {
+			p.inUse.release(weight)
+		}
+	}
+	if cfg.metadata {
+		return p.meta.checkin(obj, cfg.clock.Now(), atomic.LoadUint64(&p.epoch))
+	}
+	return nil
+}
+
+// put returns an object to the pool and reports whether it was retained.
+func (p *Pool) put(obj interface{}) bool {
+	return p.putWith(obj, putOptions{})
+}
+
+// putWith returns an object tagged with o to the pool and reports whether it
+// was retained.
+func (p *Pool) putWith(obj interface{}, o putOptions) bool {
+	if obj == nil {
+		return false
+	}
+	atomic.AddUint64(&p.puts, 1)
+	cfg := p.config()
+	meta := p.checkin(obj, cfg)
+	if cfg.clone != nil {
+		return false
+	}
+	if atomic.LoadInt32(&p.state) != stateOpen || atomic.LoadInt32(&p.pressure) == 1 {
+		p.destroy(obj, cfg)
+		return false
+	}
+	if cfg.raceChaos && raceDrop() {
+		return false
+	}
+	epoch := atomic.LoadUint64(&p.epoch)
+	if meta != nil && meta.epoch < epoch {
+		p.destroy(obj, cfg)
+		return false
+	}
+	if cfg.zeroFunc != nil {
+		cfg.zeroFunc(obj)
+	}
+	if cfg.affinity && meta != nil && !o.hint.set {
+		o.hint = meta.home
+	}
+	e := entry{obj: obj, meta: meta, epoch: epoch, priority: o.priority, hint: o.hint}
+	if cfg.sizer != nil {
+		e.size = cfg.sizer(obj)
+	}
+	// Poison before the quarantine, so writes during it are caught
+	p.poison(&e, cfg)
+	if cfg.quarantine > 0 {
+		// Release the object into the shard the Put would have used
+		e.hint = shardHint{key: p.hintedShard(e.hint, cfg), set: true}
+		p.quarantine.add(e, cfg.clock.Now().Add(cfg.quarantine).UnixNano())
+		return true
+	}
+	return p.restore(e, cfg)
+}
+
+// restore adds an idle entry to a shard and reports whether it was retained.
+// Entries that do not come from a Put are poisoned here, since Gets check all
+// of them.
+func (p *Pool) restore(e entry, cfg *config) bool {
+	p.poison(&e, cfg)
+	if cfg.group != nil && !cfg.group.reserve(e) {
+		return false
+	}
+	if cfg.maxMemory > 0 && e.size > 0 && !p.reserve(e.size, cfg.maxMemory) {
+		cfg.group.release(e)
+		return false
+	}
+	if cfg.doublePutHandler != nil && !p.idle.add(e.obj, cfg.doublePutHandler) {
+		p.unreserve(e.size)
+		cfg.group.release(e)
+		return false
+	}
+	if cfg.hotCap > 0 {
+		e.heated = cfg.clock.Now().UnixNano()
+	}
+	policy := pushPolicy(cfg)
+	shardID := p.lockPutShard(e.hint, cfg)
+	shard := &p.shards[shardID]
+	var ok bool
+	var evicted []entry
+	if cfg.hotCap > 0 {
+		ok, evicted = shard.pushHotLocked(e, cfg)
+	} else {
+		ok, evicted = shard.pushLocked(e, shard.limit(cfg), policy)
+		if !ok && cfg.softCap > 0 {
+			ok = shard.pushSoftLocked(e, cfg)
+		}
+	}
+	shard.unlock()
+	atomic.AddUint64(&shard.puts, 1)
+	for _, v := range evicted {
+		p.evicted(v, cfg)
+	}
+	if !ok && cfg.overflow == OverflowSpill {
+		ok = p.spill(e, shardID, cfg)
+	}
+	if !ok {
+		atomic.AddUint64(&p.drops, 1)
+		atomic.AddUint64(&shard.drops, 1)
+		p.removed(e)
+		if cfg.logger != nil {
+			p.logDrops(cfg)
+		}
+		return false
+	}
+	return true
+}
+
+// lockPutShard locks and returns the ID of the shard a returned entry goes to.
+// Without a hint, a contended preferred shard is passed over for the next of
+// up to stealShardCnt shards whose lock is free, since any shard will do for a
+// Put; if all of them are contended, it waits for the preferred one.
+func (p *Pool) lockPutShard(hint shardHint, cfg *config) uint64 {
+	shardID := p.hintedShard(hint, cfg)
+	if !hint.set {
+		for i := 0; i <= cfg.stealShardCnt; i++ {
+			id := (shardID + uint64(i)) & p.shardMask
+			if p.shards[id].mu.TryLock() {
+				return id
+			}
+		}
+	}
+	p.shards[shardID].mu.Lock()
+	return shardID
+}
+
+// removed does the bookkeeping for an entry that left the shards.
+func (p *Pool) removed(e entry) {
+	if p.idle.tracking() {
+		p.idle.remove(e.obj)
+	}
+	p.unreserve(e.size)
+	p.config().group.release(e)
+}
+
+// evicted does the bookkeeping for an entry that was evicted from a shard.
+func (p *Pool) evicted(e entry, cfg *config) {
+	p.removed(e)
+	if cfg.hooks.OnEvict != nil {
+		cfg.hooks.OnEvict(e.obj)
+	}
+	if cfg.logger != nil {
+		p.log(cfg, cfg.levels().Evictions, "pool evicted idle object", slog.String("type", fmt.Sprintf("%T", e.obj)))
+	}
+	p.destroy(e.obj, cfg)
+}
+
+// reserve accounts size retained bytes if the total stays within limit.
+func (p *Pool) reserve(size int, limit int64) bool {
+	if atomic.AddInt64(&p.retained, int64(size)) > limit {
+		atomic.AddInt64(&p.retained, -int64(size))
+		return false
+	}
+	return true
+}
+
+// unreserve releases size retained bytes.
+func (p *Pool) unreserve(size int) {
+	if size > 0 {
+		atomic.AddInt64(&p.retained, -int64(size))
+	}
+}
+
+// RetainedBytes returns the total size of the idle objects as reported by the
+// Sizer. It is zero if no Sizer is set.
+func (p *Pool) RetainedBytes() int64 {
+	return atomic.LoadInt64(&p.retained)
+}
+
+// pickShard returns the ID of the shard to use under cfg.
+func (p *Pool) pickShard(cfg *config) uint64 {
+	if p.sequence != nil {
+		return p.sequence.next(p.shardMask)
+	}
+	if cfg.raceChaos {
+		return p.raceShardID()
+	}
+	return p.shardID()
 }
 
 // shardID returns the ID of the shard to use.
@@ -92,39 +731,355 @@ func (p *Pool) shardIDGoID() uint64 {
 
 // Clear clears all objects from the pool.
 func (p *Pool) Clear() {
+	for i := range p.shards {
+		p.takeIdle(i)
+	}
+	p.quarantine.takeAll()
+}
+
+// ClearFunc clears all objects from the pool like Clear and calls fn with each
+// removed object, e.g. to close it. fn is called outside the shard locks.
+func (p *Pool) ClearFunc(fn func(obj interface{})) {
+	for i := range p.shards {
+		for _, e := range p.takeIdle(i) {
+			fn(e.obj)
+		}
+	}
+	for _, e := range p.quarantine.takeAll() {
+		fn(e.obj)
+	}
+}
+
+// ClearShard clears the idle objects of shard i, in [0, ShardCount), like Clear.
+func (p *Pool) ClearShard(i int) {
+	p.takeIdle(i)
+}
+
+// ClearWhere destroys the idle objects for which fn reports true, e.g. buffers
+// above a size or connections to a decommissioned host, and keeps the others
+// warm. It returns the number of destroyed objects. fn is called under the
+// shard locks, so it must not call back into the pool.
+func (p *Pool) ClearWhere(fn func(obj interface{}) bool) int {
+	cfg := p.config()
+	n := 0
 	for i := range p.shards {
 		shard := &p.shards[i]
 		shard.mu.Lock()
-		shard.objs = nil
-		shard.mu.Unlock()
+		shard.coolAll()
+		var victims []int
+		for j, e := range shard.objs {
+			if fn(e.obj) {
+				victims = append(victims, j)
+			}
+		}
+		evicted := shard.evict(victims)
+		n += len(evicted)
+		evicted = append(evicted, shard.retier(cfg)...)
+		shard.unlock()
+		for _, e := range evicted {
+			p.evicted(e, cfg)
+		}
+	}
+	return n
+}
+
+// Range calls fn for the idle objects, shard by shard under the shard's lock,
+// until fn returns false. The objects stay in the pool; fn must not keep them
+// or call back into the pool.
+func (p *Pool) Range(fn func(obj interface{}) bool) {
+	for i := range p.shards {
+		if !p.shards[i].each(fn) {
+			return
+		}
+	}
+}
+
+// takeIdle removes and returns all idle entries of shard i.
+func (p *Pool) takeIdle(i int) []entry {
+	shard := &p.shards[i]
+	shard.mu.Lock()
+	shard.coolAll()
+	objs := shard.objs
+	shard.objs = nil
+	shard.unlock()
+	for _, e := range objs {
+		p.removed(e)
+	}
+	return objs
+}
+
+// visitIdle removes the idle entries of shard i one at a time and passes each
+// to fn, which must return it to the pool or destroy it. It visits the entries
+// the shard held when it was called, soft ones first and the least recently
+// returned first within each tier, so the others keep serving Gets while fn
+// runs.
+func (p *Pool) visitIdle(i int, fn func(e entry)) {
+	shard := &p.shards[i]
+	shard.mu.Lock()
+	n := shard.idle()
+	shard.unlock()
+	for ; n > 0; n-- {
+		shard.mu.Lock()
+		e, ok := shard.popOldestLocked()
+		shard.unlock()
+		if !ok {
+			return
+		}
+		p.removed(e)
+		fn(e)
+	}
+}
+
+// popOldestLocked removes and returns the first entry of the soft tier, the
+// cold tier or the hot tier, in that order. Entries put back are appended to
+// the cold or the hot tier, so they come after those already there.
+// s.mu must be held.
+func (s *poolShard) popOldestLocked() (entry, bool) {
+	for _, tier := range []*[]entry{&s.soft, &s.objs, &s.hot} {
+		if len(*tier) > 0 {
+			e := (*tier)[0]
+			(*tier)[0] = entry{}
+			*tier = (*tier)[1:]
+			return e, true
+		}
 	}
+	return entry{}, false
+}
+
+// entry is an idle object held by a shard.
+type entry struct {
+	obj interface{}
+	// Metadata of the object, nil unless metadata tracking is enabled
+	meta *objectMeta
+	// Size of the object reported by the Sizer, zero if no Sizer is set
+	size int
+	// Epoch of the pool when the object was returned, see Pool.Invalidate
+	epoch uint64
+	// Priority class given by PutPriority
+	priority int
+	// Shard to return the object to, set by PutHint
+	hint shardHint
+	// Time the entry entered the hot tier in Unix nanoseconds, see WithHotCold
+	heated int64
+	// Time the entry decays from the soft tier in Unix nanoseconds, see WithSoftCap
+	expires int64
+	// Whether the object holds the poison pattern, see WithSanitizer
+	poisoned bool
 }
 
 // poolShard represents a single shard in the pool.
 type poolShard struct {
 	mu   sync.Mutex
-	objs []interface{}
+	objs []entry
+	// Hot tier in front of objs, empty unless WithHotCold is set
+	hot []entry
+	// Entries above the shard capacity that decay, empty unless WithSoftCap is set
+	soft []entry
+	// Objects checked out by Gets on this shard not yet checked in, see Pool.uncount
+	checkedOut int64
+	// Gets that preferred this shard, and how many of them it served
+	gets, hits uint64
+	// Objects stolen from this shard by Gets that preferred another one
+	steals uint64
+	// Objects returned to this shard
+	puts uint64
+	// Objects returned to this shard and dropped because it was full
+	drops uint64
+	// Capacity set by per-shard auto-tuning, zero to use the configured one
+	capacity int64
+	// Number of idle entries, published on unlock for lock-free emptiness checks
+	depth int32
+	// Bit of the shard in the pool's bitmap of non-empty shards
+	bit      uint64
+	nonEmpty *uint64
 }
 
-// pop removes and returns an object from the shard.
-// If the shard is empty, it returns nil.
-func (s *poolShard) pop() interface{} {
+// unlock publishes the number of idle entries and whether there are any, and
+// unlocks the shard. Shards are always unlocked through it, so depth and the
+// pool's bitmap of non-empty shards stay current.
+func (s *poolShard) unlock() {
+	n := s.idle()
+	atomic.StoreInt32(&s.depth, int32(n))
+	if s.nonEmpty != nil {
+		if n > 0 {
+			atomic.OrUint64(s.nonEmpty, s.bit)
+		} else {
+			atomic.AndUint64(s.nonEmpty, ^s.bit)
+		}
+	}
+	s.mu.Unlock()
+}
+
+// empty reports whether the shard held no idle entries when it was last
+// unlocked, without taking its lock.
+func (s *poolShard) empty() bool {
+	return atomic.LoadInt32(&s.depth) == 0
+}
+
+// limit returns the capacity of the shard under cfg.
+func (s *poolShard) limit(cfg *config) int {
+	if c := atomic.LoadInt64(&s.capacity); c > 0 {
+		return int(c)
+	}
+	return cfg.shardCap
+}
+
+// pop removes and returns an entry from the shard.
+// The entry is chosen by the eviction policy of cfg if set; otherwise it is the
+// least recently added one in FIFO mode and the most recently added one by default.
+// If the shard is empty, it returns false.
+func (s *poolShard) pop(cfg *config) (entry, bool) {
+	s.mu.Lock()
+	e, ok := s.popLocked(cfg)
+	s.unlock()
+	return e, ok
+}
+
+// popN removes and returns up to n entries from the shard, chosen like pop.
+func (s *poolShard) popN(n int, cfg *config) []entry {
+	popped := make([]entry, 0, min(n, localBatch))
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	for len(popped) < n {
+		e, ok := s.popLocked(cfg)
+		if !ok {
+			break
+		}
+		popped = append(popped, e)
+	}
+	s.unlock()
+	return popped
+}
+
+// popLocked removes and returns an entry like pop.
+// s.mu must be held.
+func (s *poolShard) popLocked(cfg *config) (entry, bool) {
+	if n := len(s.hot); n > 0 {
+		e := s.hot[n-1]
+		s.hot[n-1] = entry{}
+		s.hot = s.hot[:n-1]
+		return e, true
+	}
+	if n := len(s.soft); n > 0 {
+		e := s.soft[n-1]
+		s.soft[n-1] = entry{}
+		s.soft = s.soft[:n-1]
+		return e, true
+	}
 	if len(s.objs) == 0 {
-		return nil
+		return entry{}, false
+	}
+	if cfg.policy != nil {
+		i := len(s.objs) - 1
+		if j := cfg.policy.OnGet(idleList(s.objs)); j >= 0 && j < len(s.objs) {
+			i = j
+		}
+		return s.removeAt(i), true
+	}
+	if cfg.fifo {
+		// Reslicing from the front keeps pops O(1); append reclaims the space
+		// in front once it has to grow the backing array
+		e := s.objs[0]
+		s.objs[0] = entry{}
+		s.objs = s.objs[1:]
+		return e, true
 	}
-	obj := s.objs[len(s.objs)-1]
+	e := s.objs[len(s.objs)-1]
+	s.objs[len(s.objs)-1] = entry{}
 	s.objs = s.objs[:len(s.objs)-1]
-	return obj
+	return e, true
+}
+
+// each calls fn for the entries of the shard until it returns false, and
+// reports whether it did not.
+func (s *poolShard) each(fn func(obj interface{}) bool) bool {
+	s.mu.Lock()
+	defer s.unlock()
+	return s.eachLocked(func(e entry) bool {
+		return fn(e.obj)
+	})
+}
+
+// eachLocked calls fn for the entries of the shard, cold ones first, until it
+// returns false, and reports whether it did not.
+// s.mu must be held.
+func (s *poolShard) eachLocked(fn func(e entry) bool) bool {
+	for _, e := range s.objs {
+		if !fn(e) {
+			return false
+		}
+	}
+	for _, e := range s.hot {
+		if !fn(e) {
+			return false
+		}
+	}
+	for _, e := range s.soft {
+		if !fn(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// idle returns the number of entries of the shard.
+// s.mu must be held.
+func (s *poolShard) idle() int {
+	return len(s.objs) + len(s.hot) + len(s.soft)
 }
 
-// push adds an object to the shard.
-// If the shard has reached its capacity, the object will not be added.
-func (s *poolShard) push(obj interface{}) {
+// push adds an entry to the shard.
+// If the shard has reached its capacity, policy may select idle entries to evict
+// in favor of the new one; otherwise the entry will not be added.
+// It reports whether the entry was added and returns the evicted entries.
+func (s *poolShard) push(e entry, capacity int, policy EvictionPolicy) (bool, []entry) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	if len(s.objs) < shardCap {
-		s.objs = append(s.objs, obj)
+	ok, evicted := s.pushLocked(e, capacity, policy)
+	s.unlock()
+	return ok, evicted
+}
+
+// pushLocked is push with s.mu held.
+func (s *poolShard) pushLocked(e entry, capacity int, policy EvictionPolicy) (bool, []entry) {
+	var evicted []entry
+	if len(s.objs) >= capacity && policy != nil && capacity > 0 {
+		evicted = s.evict(policy.SelectVictims(idleList(s.objs), len(s.objs)-capacity+1))
+	}
+	if len(s.objs) >= capacity {
+		return false, evicted
+	}
+	s.objs = append(s.objs, e)
+	if policy != nil {
+		policy.OnPut(e.info())
+	}
+	return true, evicted
+}
+
+// removeAt removes and returns the entry at index i, keeping the order of the others.
+// s.mu must be held.
+func (s *poolShard) removeAt(i int) entry {
+	e := s.objs[i]
+	last := len(s.objs) - 1
+	copy(s.objs[i:], s.objs[i+1:])
+	s.objs[last] = entry{}
+	s.objs = s.objs[:last]
+	return e
+}
+
+// evict removes and returns the entries at the valid, distinct indexes in victims.
+// s.mu must be held.
+func (s *poolShard) evict(victims []int) []entry {
+	if len(victims) == 0 {
+		return nil
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(victims)))
+	var evicted []entry
+	prev := -1
+	for _, i := range victims {
+		if i < 0 || i >= len(s.objs) || i == prev {
+			continue
+		}
+		prev = i
+		evicted = append(evicted, s.removeAt(i))
 	}
+	return evicted
 }
@@ -1,8 +1,14 @@
 package pool
 
 import (
+	"context"
+	"errors"
+	"runtime/pprof"
 	"sync"
 	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // TestBasic tests the basic functionality of Get and Put methods.
@@ -56,6 +62,120 @@ func TestClear(t *testing.T) {
 	}
 }
 
+// TestClearFunc tests that ClearFunc calls the callback on each removed object.
+func TestClearFunc(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	for i := 0; i < 3; i++ {
+		p.Put(new(int))
+	}
+
+	removed := 0
+	p.ClearFunc(func(obj interface{}) {
+		removed++
+	})
+	if removed != 3 {
+		t.Errorf("Expected 3 removed objects, got %d", removed)
+	}
+	if n := idleCount(p); n != 0 {
+		t.Errorf("Expected no idle objects, got %d", n)
+	}
+}
+
+// TestClearShard tests that ClearShard only clears the given shard.
+func TestClearShard(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	for i := 0; i < 2; i++ {
+		putShard(p, uint64(i), new(int))
+	}
+
+	p.ClearShard(0)
+	if !p.shards[0].empty() || p.shards[1].empty() {
+		t.Error("Expected only shard 0 to be cleared")
+	}
+}
+
+// TestClearWhere tests that ClearWhere destroys exactly the matching objects.
+func TestClearWhere(t *testing.T) {
+	destroyed := 0
+	p := predictable(NewPool(func() interface{} {
+		return make([]byte, 8)
+	}, WithDestructor(func(obj interface{}) {
+		destroyed++
+	})))
+	for _, size := range []int{8, 1024, 16, 4096} {
+		p.Put(make([]byte, size))
+	}
+
+	n := p.ClearWhere(func(obj interface{}) bool {
+		return len(obj.([]byte)) > 512
+	})
+	if n != 2 || destroyed != 2 {
+		t.Errorf("Expected 2 large buffers to be destroyed, got %d and %d", n, destroyed)
+	}
+	p.Range(func(obj interface{}) bool {
+		if len(obj.([]byte)) > 512 {
+			t.Errorf("Expected no large buffer to remain, got %d bytes", len(obj.([]byte)))
+		}
+		return true
+	})
+	if n := idleCount(p); n != 2 {
+		t.Errorf("Expected 2 small buffers to stay idle, got %d", n)
+	}
+}
+
+// TestRange tests that Range visits idle objects and stops when asked.
+func TestRange(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	for i := 0; i < 3; i++ {
+		p.Put(new(int))
+	}
+
+	visited := 0
+	p.Range(func(obj interface{}) bool {
+		visited++
+		return true
+	})
+	if visited != 3 {
+		t.Errorf("Expected 3 visited objects, got %d", visited)
+	}
+	visited = 0
+	p.Range(func(obj interface{}) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Errorf("Expected Range to stop after 1 object, got %d", visited)
+	}
+	if n := idleCount(p); n != 3 {
+		t.Errorf("Expected objects to stay in the pool, got %d", n)
+	}
+}
+
+// TestPutCheck tests that PutCheck reports whether the object was retained.
+func TestPutCheck(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	if !p.PutCheck(p.Get()) {
+		t.Error("Expected the object to be retained")
+	}
+	if p.PutCheck(nil) {
+		t.Error("Expected nil not to be retained")
+	}
+	p.updateConfig(func(c *config) {
+		c.shardCap = 0
+	})
+	if p.PutCheck(new(int)) {
+		t.Error("Expected the object to be declined by a full pool")
+	}
+}
+
 // TestCapacity tests the capacity limit of the Pool.
 func TestCapacity(t *testing.T) {
 	p := NewPool(func() interface{} {
@@ -101,6 +221,273 @@ func TestShardDistribution(t *testing.T) {
 	}
 }
 
+// TestGetE tests that factory errors are returned by GetE.
+func TestGetE(t *testing.T) {
+	errTest := errors.New("test")
+	fail := true
+	p := NewPoolE(func() (interface{}, error) {
+		if fail {
+			return nil, errTest
+		}
+		return new(int), nil
+	})
+
+	if obj, err := p.GetE(); obj != nil || err != errTest {
+		t.Errorf("Expected factory error, got %v, %v", obj, err)
+	}
+	if obj := p.Get(); obj != nil {
+		t.Error("Expected nil object from Get when the factory fails")
+	}
+
+	fail = false
+	if obj, err := p.GetE(); obj == nil || err != nil {
+		t.Errorf("Expected object from GetE, got %v, %v", obj, err)
+	}
+}
+
+// TestGetContext tests that the context is passed to the context-aware factory.
+func TestGetContext(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithFactoryContext(func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return new(int), nil
+		}
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.GetContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if _, err := p.GetContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected an expired context to fail fast, got %v", err)
+	}
+}
+
+// TestProfileLabel tests that the factory runs under the pool's pprof label.
+func TestProfileLabel(t *testing.T) {
+	var label string
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithProfileLabel("ints"), WithFactoryContext(func(ctx context.Context) (interface{}, error) {
+		label, _ = pprof.Label(ctx, "pool")
+		return new(int), nil
+	}))
+	p.Get()
+
+	if label != "ints" {
+		t.Errorf("Expected label pool=ints, got %q", label)
+	}
+}
+
+// TestMaxInUse tests that Get fails fast once the in-use limit is reached.
+func TestMaxInUse(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithMaxInUse(2, false))
+
+	a, _ := p.GetE()
+	b, _ := p.GetE()
+	if _, err := p.GetE(); err != ErrExhausted {
+		t.Errorf("Expected ErrExhausted, got %v", err)
+	}
+
+	p.Put(a)
+	p.Discard(b)
+	for i := 0; i < 2; i++ {
+		if _, err := p.GetE(); err != nil {
+			t.Errorf("Expected Put and Discard to free slots, got %v", err)
+		}
+	}
+}
+
+// TestMaxInUseWait tests that Get waits for an object to be returned.
+func TestMaxInUseWait(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithMaxInUse(1, true))
+
+	obj := p.Get()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.GetContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.Put(obj)
+	}()
+	if p.Get() == nil {
+		t.Error("Expected Get to succeed once the object is returned")
+	}
+}
+
+// TestGetWith tests that GetWith passes its arguments to the factory on a miss.
+func TestGetWith(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return make([]byte, 0, 8)
+	}, WithArgsFactory(func(args any) interface{} {
+		return make([]byte, 0, args.(int))
+	})))
+
+	if buf := p.GetWith(64).([]byte); cap(buf) != 64 {
+		t.Errorf("Expected a buffer sized by the arguments, got cap %d", cap(buf))
+	}
+	if buf := p.Get().([]byte); cap(buf) != 8 {
+		t.Errorf("Expected Get to use the pool's factory, got cap %d", cap(buf))
+	}
+	p.Put(make([]byte, 0, 16))
+	if buf := p.GetWith(64).([]byte); cap(buf) != 16 {
+		t.Errorf("Expected the idle buffer to be reused, got cap %d", cap(buf))
+	}
+}
+
+// TestErrorVariants tests that the error-returning variants fail with the sentinel errors.
+func TestErrorVariants(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithMaxInUse(1, true))
+
+	obj := p.Get()
+	if _, err := p.GetTimeout(10 * time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+	if err := p.PutE(obj); err != nil {
+		t.Errorf("Expected PutE to succeed on an open pool, got %v", err)
+	}
+	if err := p.Drain(context.Background()); err != nil {
+		t.Fatalf("Expected Drain to succeed, got %v", err)
+	}
+	if _, err := p.GetTimeout(time.Second); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+
+	p = NewPool(func() interface{} {
+		return new(int)
+	})
+	obj = p.Get()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Drain(ctx); err == nil {
+		t.Error("Expected Drain to give up with an object checked out")
+	}
+	if err := p.PutE(obj); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed from PutE, got %v", err)
+	}
+}
+
+// TestNewRateLimit tests that misses beyond the factory rate limit fail or wait.
+func TestNewRateLimit(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithNewRateLimit(rate.Every(time.Hour), false))
+
+	if _, err := p.GetE(); err != nil {
+		t.Errorf("Expected the first miss to be allowed, got %v", err)
+	}
+	if _, err := p.GetE(); err != ErrRateLimited {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+
+	p = NewPool(func() interface{} {
+		return new(int)
+	}, WithNewRateLimit(rate.Every(10*time.Millisecond), true))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := p.GetE(); err != nil {
+			t.Errorf("Expected waiting misses to succeed, got %v", err)
+		}
+	}
+	if d := time.Since(start); d < 15*time.Millisecond {
+		t.Errorf("Expected misses to be spaced by the limit, took %v", d)
+	}
+}
+
+// TestPutContended tests that Put passes over locked shards instead of waiting.
+func TestPutContended(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	const free = 5
+	for i := range p.shards {
+		if i != free {
+			p.shards[i].mu.Lock()
+		}
+	}
+	retained := p.PutCheck(new(int))
+	for i := range p.shards {
+		if i != free {
+			p.shards[i].mu.Unlock()
+		}
+	}
+
+	if !retained {
+		t.Error("Expected the object to be retained")
+	}
+	if p.shards[free].empty() {
+		t.Error("Expected the object in the only free shard")
+	}
+}
+
+// TestGetOr tests that GetOr creates objects with its factory only on a miss.
+func TestGetOr(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return 0
+	}))
+
+	if obj := p.GetOr(func() interface{} { return 1 }); obj != 1 {
+		t.Errorf("Expected the per-call factory to create the object, got %v", obj)
+	}
+	p.Put(2)
+	if obj := p.GetOr(func() interface{} { return 1 }); obj != 2 {
+		t.Errorf("Expected the idle object to be reused, got %v", obj)
+	}
+	if obj := p.Get(); obj != 0 {
+		t.Errorf("Expected Get to use the pool's factory, got %v", obj)
+	}
+}
+
+// idleCount returns the number of idle objects held by all shards of p.
+func idleCount(p *Pool) int {
+	n := 0
+	for i := range p.shards {
+		shard := &p.shards[i]
+		shard.mu.Lock()
+		n += shard.idle()
+		shard.mu.Unlock()
+	}
+	return n
+}
+
+// predictable makes Get of p search every shard and disables race chaos,
+// so tests do not depend on shard selection or random drops.
+func predictable(p *Pool) *Pool {
+	p.updateConfig(func(c *config) {
+		c.stealShardCnt = len(p.shards)
+		c.raceChaos = false
+	})
+	return p
+}
+
+// putShard returns obj to shard i of p, unlike PutHint, whose hints are mixed.
+func putShard(p *Pool, i uint64, obj interface{}) {
+	p.putHooked(obj, putOptions{hint: shardHint{key: i, set: true}})
+}
+
+// unsanitized turns off the sanitizer of p, which pool_sanitize builds enable
+// for every pool, and returns p.
+func unsanitized(p *Pool) *Pool {
+	p.updateConfig(func(c *config) {
+		c.sanitizeHandler = nil
+	})
+	return p
+}
+
 // BenchmarkCustomPool tests the performance of the custom Pool.
 func BenchmarkCustomPool(b *testing.B) {
 	p := NewPool(func() interface{} {
@@ -168,3 +555,54 @@ func BenchmarkSyncPoolParallel(b *testing.B) {
 	}
 	wg.Wait()
 }
+
+// BenchmarkShardPushPop tests the performance of a shard's push and pop.
+func BenchmarkShardPushPop(b *testing.B) {
+	var s poolShard
+	cfg := defaultConfig()
+	e := entry{obj: new(int)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.push(e, shardCap, nil)
+		s.pop(cfg)
+	}
+}
+
+// BenchmarkShardPushPopDefer tests the performance of push and pop unlocking
+// with defer, the baseline of BenchmarkShardPushPop.
+func BenchmarkShardPushPopDefer(b *testing.B) {
+	var s poolShard
+	cfg := defaultConfig()
+	e := entry{obj: new(int)}
+	push := func() (bool, []entry) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.pushLocked(e, shardCap, nil)
+	}
+	pop := func() (entry, bool) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.popLocked(cfg)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		push()
+		pop()
+	}
+}
+
+// BenchmarkShardPushPopParallel tests the performance of a contended shard.
+func BenchmarkShardPushPopParallel(b *testing.B) {
+	var s poolShard
+	cfg := defaultConfig()
+
+	b.RunParallel(func(pb *testing.PB) {
+		e := entry{obj: new(int)}
+		for pb.Next() {
+			s.push(e, shardCap, nil)
+			s.pop(cfg)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+// Package poolbench benchmarks the pooling backends across object sizes,
+// goroutine counts and hold times, so configurations can be picked from data
+// instead of ad-hoc benchmarks.
+package poolbench
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ongniud/pool"
+)
+
+// Config describes the benchmark matrix; every combination is run once.
+type Config struct {
+	// Backends to compare, all of them if empty
+	Backends []pool.Backend
+	// Sizes of the pooled byte slices
+	Sizes []int
+	// Goroutines running Get/Put loops concurrently
+	Goroutines []int
+	// HoldTimes each goroutine keeps an object before returning it
+	HoldTimes []time.Duration
+	// Ops is the number of Get/Put pairs per goroutine
+	Ops int
+}
+
+// Result is the outcome of one combination of the matrix.
+type Result struct {
+	Backend    pool.Backend
+	Size       int
+	Goroutines int
+	Hold       time.Duration
+	// Elapsed is the wall time of the run
+	Elapsed time.Duration
+	// NsPerOp is the wall time per Get/Put pair
+	NsPerOp float64
+	// AllocsPerOp and BytesPerOp are the heap allocations per Get/Put pair
+	AllocsPerOp float64
+	BytesPerOp  float64
+}
+
+// Report is the outcome of a benchmark run.
+type Report struct {
+	Results []Result
+}
+
+// allBackends are the backends compared by default.
+var allBackends = []pool.Backend{pool.BackendSharded, pool.BackendSync, pool.BackendHybrid, pool.BackendNoop}
+
+// Run runs every combination of cfg and returns the report.
+func Run(cfg Config) Report {
+	backends := cfg.Backends
+	if len(backends) == 0 {
+		backends = allBackends
+	}
+	var r Report
+	for _, size := range cfg.Sizes {
+		for _, g := range cfg.Goroutines {
+			for _, hold := range cfg.HoldTimes {
+				for _, b := range backends {
+					r.Results = append(r.Results, run(b, size, g, hold, cfg.Ops))
+				}
+			}
+		}
+	}
+	return r
+}
+
+// run benchmarks a single combination.
+func run(backend pool.Backend, size, goroutines int, hold time.Duration, ops int) Result {
+	p := pool.NewPooler(backend, func() interface{} {
+		b := make([]byte, size)
+		return &b
+	})
+	// Warm up so the run measures steady-state reuse
+	for i := 0; i < goroutines; i++ {
+		p.Put(p.Get())
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < ops; j++ {
+				b := p.Get().(*[]byte)
+				(*b)[0]++
+				spin(hold)
+				p.Put(b)
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	total := float64(goroutines * ops)
+	if total == 0 {
+		total = 1
+	}
+	return Result{
+		Backend:     backend,
+		Size:        size,
+		Goroutines:  goroutines,
+		Hold:        hold,
+		Elapsed:     elapsed,
+		NsPerOp:     float64(elapsed.Nanoseconds()) / total,
+		AllocsPerOp: float64(after.Mallocs-before.Mallocs) / total,
+		BytesPerOp:  float64(after.TotalAlloc-before.TotalAlloc) / total,
+	}
+}
+
+// spin busy-waits for d, which is more precise than sleeping for short holds.
+func spin(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	for start := time.Now(); time.Since(start) < d; {
+	}
+}
+
+// WriteText writes the report to w as an aligned table.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "backend\tsize\tgoroutines\thold\tns/op\tallocs/op\tB/op\t")
+	for _, res := range r.Results {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%.1f\t%.2f\t%.1f\t\n",
+			res.Backend, res.Size, res.Goroutines, res.Hold, res.NsPerOp, res.AllocsPerOp, res.BytesPerOp)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,37 @@
+package poolbench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ongniud/pool"
+)
+
+// TestRun tests that every combination of the matrix is run and reported.
+func TestRun(t *testing.T) {
+	r := Run(Config{
+		Backends:   []pool.Backend{pool.BackendSharded, pool.BackendNoop},
+		Sizes:      []int{64, 1024},
+		Goroutines: []int{1, 2},
+		HoldTimes:  []time.Duration{0},
+		Ops:        100,
+	})
+	if len(r.Results) != 8 {
+		t.Fatalf("Expected 8 results, got %d", len(r.Results))
+	}
+	for _, res := range r.Results {
+		if res.NsPerOp <= 0 {
+			t.Errorf("Expected a positive time per op, got %+v", res)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 9 {
+		t.Errorf("Expected a header and 8 rows, got %d lines:\n%s", lines, buf.String())
+	}
+}
@@ -0,0 +1,81 @@
+package pool
+
+import (
+	"bytes"
+	"io"
+)
+
+// PooledBuffer is a buffer from a BufferPool behind the io interfaces. Close
+// returns its storage to the pool, so it can be handed to code that only
+// knows io.ReadWriteCloser, such as a response body, and is reclaimed when
+// that code closes it.
+type PooledBuffer struct {
+	bp  *BufferPool
+	buf *bytes.Buffer
+}
+
+// GetPooled retrieves an empty pooled buffer with a capacity of at least
+// minSize.
+func (bp *BufferPool) GetPooled(minSize int) *PooledBuffer {
+	return &PooledBuffer{bp: bp, buf: bp.GetBuffer(minSize)}
+}
+
+// Read reads the next bytes of the buffer.
+func (pb *PooledBuffer) Read(p []byte) (int, error) {
+	if pb.buf == nil {
+		return 0, ErrBufferClosed
+	}
+	return pb.buf.Read(p)
+}
+
+// Write appends p to the buffer.
+func (pb *PooledBuffer) Write(p []byte) (int, error) {
+	if pb.buf == nil {
+		return 0, ErrBufferClosed
+	}
+	return pb.buf.Write(p)
+}
+
+// WriteTo writes the unread bytes of the buffer to w.
+func (pb *PooledBuffer) WriteTo(w io.Writer) (int64, error) {
+	if pb.buf == nil {
+		return 0, ErrBufferClosed
+	}
+	return pb.buf.WriteTo(w)
+}
+
+// ReadFrom appends the data read from r until EOF to the buffer.
+func (pb *PooledBuffer) ReadFrom(r io.Reader) (int64, error) {
+	if pb.buf == nil {
+		return 0, ErrBufferClosed
+	}
+	return pb.buf.ReadFrom(r)
+}
+
+// Len returns the number of unread bytes, or 0 after Close.
+func (pb *PooledBuffer) Len() int {
+	if pb.buf == nil {
+		return 0
+	}
+	return pb.buf.Len()
+}
+
+// Bytes returns the unread bytes, which are only valid until the next
+// modification of the buffer or Close.
+func (pb *PooledBuffer) Bytes() []byte {
+	if pb.buf == nil {
+		return nil
+	}
+	return pb.buf.Bytes()
+}
+
+// Close returns the storage to the pool. Later calls return ErrBufferClosed.
+func (pb *PooledBuffer) Close() error {
+	if pb.buf == nil {
+		return ErrBufferClosed
+	}
+	buf := pb.buf
+	pb.buf = nil
+	pb.bp.Put(buf)
+	return nil
+}
@@ -0,0 +1,44 @@
+package pool
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// TestPooledBuffer tests the io methods and that Close returns the storage.
+func TestPooledBuffer(t *testing.T) {
+	bp := NewBufferPool()
+	for _, p := range bp.classes {
+		predictable(p)
+	}
+
+	pb := bp.GetPooled(100)
+	var _ io.ReadWriteCloser = pb
+	if _, err := io.WriteString(pb, "hello world"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	head := make([]byte, 6)
+	if _, err := io.ReadFull(pb, head); err != nil || string(head) != "hello " {
+		t.Fatalf("Expected to read %q, got %q and %v", "hello ", head, err)
+	}
+	var out bytes.Buffer
+	if n, err := pb.WriteTo(&out); err != nil || n != 5 || out.String() != "world" {
+		t.Errorf("Expected WriteTo to write %q, got %q, %d and %v", "world", out.String(), n, err)
+	}
+
+	storage := pb.buf
+	if err := pb.Close(); err != nil {
+		t.Fatalf("Expected no error from Close, got %v", err)
+	}
+	if _, err := pb.Write([]byte("x")); !errors.Is(err, ErrBufferClosed) {
+		t.Errorf("Expected ErrBufferClosed after Close, got %v", err)
+	}
+	if err := pb.Close(); !errors.Is(err, ErrBufferClosed) {
+		t.Errorf("Expected ErrBufferClosed from a second Close, got %v", err)
+	}
+	if bp.GetBuffer(100) != storage {
+		t.Error("Expected Close to return the storage to the pool")
+	}
+}
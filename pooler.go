@@ -0,0 +1,206 @@
+package pool
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"weak"
+)
+
+// Pooler is the interface implemented by all pooling backends.
+// Libraries should accept a Pooler instead of *Pool so callers can swap in a
+// different implementation.
+type Pooler interface {
+	// Get retrieves an object, creating a new one if none is available.
+	Get() interface{}
+	// Put returns an object. Implementations may drop it.
+	Put(obj interface{})
+	// Clear drops all pooled objects.
+	Clear()
+}
+
+// BackendEnv is the environment variable consulted by NewPooler when no backend is given.
+const BackendEnv = "POOL_BACKEND"
+
+// Backend names a Pooler implementation.
+type Backend string
+
+const (
+	// BackendSharded selects Pool
+	BackendSharded Backend = "sharded"
+	// BackendSync selects SyncPool
+	BackendSync Backend = "sync"
+	// BackendHybrid selects HybridPool
+	BackendHybrid Backend = "hybrid"
+	// BackendNoop selects NoopPool, which disables pooling
+	BackendNoop Backend = "noop"
+)
+
+// NewPooler creates a Pooler for the given backend.
+// If backend is empty, it is read from the POOL_BACKEND environment variable,
+// so pooling can be switched on and off without code changes.
+// Empty or unknown backends select the sharded Pool.
+func NewPooler(backend Backend, fn func() interface{}) Pooler {
+	if backend == "" {
+		backend = Backend(os.Getenv(BackendEnv))
+	}
+	switch backend {
+	case BackendSync:
+		return NewSyncPool(fn)
+	case BackendHybrid:
+		return NewHybridPool(fn)
+	case BackendNoop:
+		return NewNoopPool(fn)
+	default:
+		return NewPool(fn)
+	}
+}
+
+var (
+	_ Pooler = (*Pool)(nil)
+	_ Pooler = (*SyncPool)(nil)
+	_ Pooler = (*NoopPool)(nil)
+	_ Pooler = (*HybridPool)(nil)
+)
+
+// SyncPool adapts sync.Pool to the Pooler interface.
+type SyncPool struct {
+	newFunc func() interface{}
+	pool    atomic.Pointer[sync.Pool]
+}
+
+// NewSyncPool creates a Pooler backed by sync.Pool.
+// fn is the function used to create a new object when the pool is empty.
+func NewSyncPool(fn func() interface{}) *SyncPool {
+	if fn == nil {
+		panic("newFunc cannot be nil")
+	}
+	p := &SyncPool{newFunc: fn}
+	p.pool.Store(&sync.Pool{New: fn})
+	return p
+}
+
+// WrapSyncPool adapts an existing sync.Pool to the Pooler interface.
+// Clear replaces sp with a new sync.Pool sharing its New function.
+func WrapSyncPool(sp *sync.Pool) *SyncPool {
+	if sp == nil {
+		panic("sync.Pool cannot be nil")
+	}
+	p := &SyncPool{newFunc: sp.New}
+	p.pool.Store(sp)
+	return p
+}
+
+// Get retrieves an object from the underlying sync.Pool.
+func (p *SyncPool) Get() interface{} {
+	return p.pool.Load().Get()
+}
+
+// Put returns an object to the underlying sync.Pool.
+// If the object is nil, it will be ignored.
+func (p *SyncPool) Put(obj interface{}) {
+	if obj == nil {
+		return
+	}
+	p.pool.Load().Put(obj)
+}
+
+// Clear drops all pooled objects by replacing the underlying sync.Pool.
+func (p *SyncPool) Clear() {
+	p.pool.Store(&sync.Pool{New: p.newFunc})
+}
+
+// HybridPool combines a sync.Pool front cache with a sharded Pool as a bounded
+// backing store. Put goes to the front cache, so the common case runs at
+// sync.Pool's per-P speed, and Get prefers it as well. After every GC cycle,
+// the objects the front cache is about to lose are moved to the store until
+// it is full, so a warm working set survives GC cycles instead of going away
+// with them.
+type HybridPool struct {
+	front atomic.Pointer[sync.Pool]
+	back  *Pool
+}
+
+// NewHybridPool creates a new hybrid pool.
+// fn is the function used to create a new object when both tiers are empty.
+func NewHybridPool(fn func() interface{}) *HybridPool {
+	p := &HybridPool{back: NewPool(fn)}
+	p.front.Store(&sync.Pool{})
+	wp := weak.Make(p)
+	onGC(func() bool {
+		p := wp.Value()
+		if p == nil {
+			return false
+		}
+		p.spill()
+		return true
+	})
+	return p
+}
+
+// spill moves objects from the front cache to the store until either is
+// empty or the store is full. It runs after a GC cycle, when the front cache
+// holds the objects it drops on the next one.
+func (p *HybridPool) spill() {
+	front := p.front.Load()
+	for {
+		obj := front.Get()
+		if obj == nil {
+			return
+		}
+		if !p.back.put(obj) {
+			front.Put(obj)
+			return
+		}
+	}
+}
+
+// Get retrieves an object from the front cache, then from the bounded store,
+// and creates a new object if both are empty.
+func (p *HybridPool) Get() interface{} {
+	if obj := p.front.Load().Get(); obj != nil {
+		return obj
+	}
+	return p.back.Get()
+}
+
+// Put returns an object to the front cache.
+// If the object is nil, it will be ignored.
+func (p *HybridPool) Put(obj interface{}) {
+	if obj == nil {
+		return
+	}
+	p.front.Load().Put(obj)
+}
+
+// Clear clears both tiers.
+func (p *HybridPool) Clear() {
+	p.front.Store(&sync.Pool{})
+	p.back.Clear()
+}
+
+// NoopPool is a Pooler that never retains objects.
+// Get always creates a new object and Put drops it.
+type NoopPool struct {
+	newFunc func() interface{}
+}
+
+// NewNoopPool creates a new no-op pool.
+// fn is the function used to create a new object on every Get.
+func NewNoopPool(fn func() interface{}) *NoopPool {
+	if fn == nil {
+		panic("newFunc cannot be nil")
+	}
+	return &NoopPool{newFunc: fn}
+}
+
+// Get creates a new object.
+func (p *NoopPool) Get() interface{} {
+	return p.newFunc()
+}
+
+// Put drops the object.
+func (p *NoopPool) Put(obj interface{}) {}
+
+// Clear does nothing.
+func (p *NoopPool) Clear() {}
@@ -0,0 +1,103 @@
+package pool
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestPoolers tests that every Pooler implementation hands out usable objects.
+func TestPoolers(t *testing.T) {
+	fn := func() interface{} {
+		return new(int)
+	}
+	poolers := map[string]Pooler{
+		"pool":   NewPool(fn),
+		"sync":   NewSyncPool(fn),
+		"noop":   NewNoopPool(fn),
+		"wrap":   WrapSyncPool(&sync.Pool{New: fn}),
+		"hybrid": NewHybridPool(fn),
+	}
+	for name, p := range poolers {
+		obj := p.Get()
+		if _, ok := obj.(*int); !ok {
+			t.Errorf("%s: expected *int from Get, got %T", name, obj)
+		}
+		p.Put(obj)
+		p.Put(nil)
+		p.Clear()
+		if p.Get() == nil {
+			t.Errorf("%s: expected non-nil object from Get after Clear", name)
+		}
+	}
+}
+
+// TestNoopPool tests that NoopPool never reuses objects.
+func TestNoopPool(t *testing.T) {
+	calls := 0
+	p := NewNoopPool(func() interface{} {
+		calls++
+		return new(int)
+	})
+
+	obj := p.Get()
+	p.Put(obj)
+	if p.Get() == obj {
+		t.Error("Expected NoopPool not to reuse objects")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 factory calls, got %d", calls)
+	}
+}
+
+// TestHybridPoolSpill tests that HybridPool puts into the front cache and
+// spills into the store after a GC cycle.
+func TestHybridPoolSpill(t *testing.T) {
+	p := NewHybridPool(func() interface{} {
+		return new(int)
+	})
+	predictable(p.back)
+	for i := 0; i < 20; i++ {
+		p.Put(new(int))
+	}
+	if n := idleCount(p.back); n != 0 {
+		t.Errorf("Expected Put to use the front cache, got %d idle in the store", n)
+	}
+
+	// The front cache may drop objects at any time, so only check the store
+	p.spill()
+	if n := idleCount(p.back); n == 0 || n > 20 {
+		t.Errorf("Expected the front cache to spill into the store, got %d idle", n)
+	}
+
+	p.Clear()
+	p.back.updateConfig(func(c *config) {
+		c.shardCap = 0
+	})
+	p.Put(new(int))
+	p.spill()
+	if n := idleCount(p.back); n != 0 {
+		t.Errorf("Expected the full store to reject the object, got %d idle", n)
+	}
+}
+
+// TestNewPooler tests backend selection by argument and environment variable.
+func TestNewPooler(t *testing.T) {
+	fn := func() interface{} {
+		return new(int)
+	}
+
+	if _, ok := NewPooler(BackendNoop, fn).(*NoopPool); !ok {
+		t.Error("Expected NoopPool for the noop backend")
+	}
+	if _, ok := NewPooler("unknown", fn).(*Pool); !ok {
+		t.Error("Expected Pool for an unknown backend")
+	}
+
+	t.Setenv(BackendEnv, string(BackendSync))
+	if _, ok := NewPooler("", fn).(*SyncPool); !ok {
+		t.Error("Expected SyncPool selected by the environment")
+	}
+	if _, ok := NewPooler(BackendHybrid, fn).(*HybridPool); !ok {
+		t.Error("Expected an explicit backend to override the environment")
+	}
+}
@@ -0,0 +1,104 @@
+package pooltest
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/ongniud/pool"
+)
+
+// ChaosConfig sets the probabilities (0 to 1) of the disturbances of a ChaosPool.
+type ChaosConfig struct {
+	// Fresh is the probability that Get returns a new object from the factory
+	// instead of a pooled one
+	Fresh float64
+	// Drop is the probability that Put drops the object
+	Drop float64
+	// Delay is the probability that Put returns the object to the pool only
+	// after a random delay of up to MaxDelay
+	Delay float64
+	// MaxDelay bounds the delay of delayed Puts, 10ms if zero
+	MaxDelay time.Duration
+	// Clear is the probability that a Put clears the whole pool
+	Clear float64
+	// Seed makes the disturbances reproducible for a single goroutine
+	Seed uint64
+}
+
+// ChaosPool is a Pooler that randomly disturbs the wrapped Pooler, to flush out
+// code that wrongly assumes objects are reused or retained. It is meant for
+// integration tests and is safe for concurrent use.
+type ChaosPool struct {
+	pool pool.Pooler
+	fn   func() interface{}
+	cfg  ChaosConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+	wg  sync.WaitGroup
+}
+
+// NewChaosPool creates a ChaosPool wrapping p; fn creates the fresh objects.
+func NewChaosPool(p pool.Pooler, fn func() interface{}, cfg ChaosConfig) *ChaosPool {
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = 10 * time.Millisecond
+	}
+	return &ChaosPool{
+		pool: p,
+		fn:   fn,
+		cfg:  cfg,
+		rng:  rand.New(rand.NewPCG(cfg.Seed, cfg.Seed)),
+	}
+}
+
+// Get retrieves an object from the wrapped Pooler, or sometimes a fresh one.
+func (c *ChaosPool) Get() interface{} {
+	if c.chance(c.cfg.Fresh) {
+		return c.fn()
+	}
+	return c.pool.Get()
+}
+
+// Put returns an object to the wrapped Pooler, sometimes dropping it, delaying
+// it or clearing the pool instead.
+func (c *ChaosPool) Put(obj interface{}) {
+	switch {
+	case c.chance(c.cfg.Drop):
+		return
+	case c.chance(c.cfg.Clear):
+		c.pool.Clear()
+		c.pool.Put(obj)
+	case c.chance(c.cfg.Delay):
+		d := time.Duration(c.float() * float64(c.cfg.MaxDelay))
+		c.wg.Add(1)
+		time.AfterFunc(d, func() {
+			defer c.wg.Done()
+			c.pool.Put(obj)
+		})
+	default:
+		c.pool.Put(obj)
+	}
+}
+
+// Clear clears the wrapped Pooler.
+func (c *ChaosPool) Clear() {
+	c.pool.Clear()
+}
+
+// Wait waits for the delayed Puts to reach the wrapped Pooler.
+func (c *ChaosPool) Wait() {
+	c.wg.Wait()
+}
+
+// chance reports whether an event of probability prob happens.
+func (c *ChaosPool) chance(prob float64) bool {
+	return prob > 0 && c.float() < prob
+}
+
+// float returns a random number in [0, 1).
+func (c *ChaosPool) float() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64()
+}
@@ -0,0 +1,72 @@
+package pooltest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ongniud/pool"
+)
+
+// counter is a Pooler that counts the calls reaching it.
+type counter struct {
+	gets, puts, clears int
+}
+
+func (c *counter) Get() interface{}    { c.gets++; return new(int) }
+func (c *counter) Put(obj interface{}) { c.puts++ }
+func (c *counter) Clear()              { c.clears++ }
+
+var _ pool.Pooler = (*counter)(nil)
+
+// TestChaosPoolFresh tests that Gets return fresh objects with the configured probability.
+func TestChaosPoolFresh(t *testing.T) {
+	inner := &counter{}
+	fresh := 0
+	c := NewChaosPool(inner, func() interface{} {
+		fresh++
+		return new(int)
+	}, ChaosConfig{Fresh: 1})
+	c.Get()
+	c.Get()
+	if fresh != 2 || inner.gets != 0 {
+		t.Errorf("Expected only fresh objects, got %d fresh and %d pooled", fresh, inner.gets)
+	}
+}
+
+// TestChaosPoolPut tests that Puts are dropped, cleared and delayed as configured.
+func TestChaosPoolPut(t *testing.T) {
+	inner := &counter{}
+	newInt := func() interface{} { return new(int) }
+
+	NewChaosPool(inner, newInt, ChaosConfig{Drop: 1}).Put(new(int))
+	if inner.puts != 0 {
+		t.Errorf("Expected the Put to be dropped, got %d", inner.puts)
+	}
+
+	NewChaosPool(inner, newInt, ChaosConfig{Clear: 1}).Put(new(int))
+	if inner.clears != 1 || inner.puts != 1 {
+		t.Errorf("Expected the pool to be cleared before the Put, got %d clears and %d puts", inner.clears, inner.puts)
+	}
+
+	c := NewChaosPool(inner, newInt, ChaosConfig{Delay: 1, MaxDelay: time.Millisecond})
+	c.Put(new(int))
+	c.Wait()
+	if inner.puts != 2 {
+		t.Errorf("Expected the delayed Put to arrive, got %d puts", inner.puts)
+	}
+}
+
+// TestChaosPoolSeed tests that the same seed disturbs the same calls.
+func TestChaosPoolSeed(t *testing.T) {
+	run := func() int {
+		inner := &counter{}
+		c := NewChaosPool(inner, func() interface{} { return new(int) }, ChaosConfig{Fresh: 0.5, Seed: 1})
+		for i := 0; i < 100; i++ {
+			c.Get()
+		}
+		return inner.gets
+	}
+	if a, b := run(), run(); a != b || a == 0 || a == 100 {
+		t.Errorf("Expected the same share of pooled Gets for the same seed, got %d and %d", a, b)
+	}
+}
@@ -0,0 +1,90 @@
+package pooltest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ongniud/pool"
+)
+
+// ManualClock is a pool.Clock whose time only moves when Advance is called,
+// for deterministic tests of time-dependent pool features. It is safe for
+// concurrent use.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*manualTicker
+}
+
+var _ pool.Clock = (*ManualClock)(nil)
+
+// NewManualClock creates a manual clock starting at start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker returns a ticker that ticks whenever Advance moves the clock past
+// its next tick. Like a time.Ticker, it drops ticks for slow receivers.
+func (c *ManualClock) NewTicker(d time.Duration) pool.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &manualTicker{clock: c, d: d, next: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and fires the tickers that are due.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		for !t.next.After(c.now) {
+			select {
+			case t.c <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.d)
+		}
+	}
+}
+
+// Tickers returns the number of running tickers, e.g. to wait for the
+// background tasks of a pool to start.
+func (c *ManualClock) Tickers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.tickers)
+}
+
+// manualTicker is a Ticker of a ManualClock.
+type manualTicker struct {
+	clock *ManualClock
+	d     time.Duration
+	next  time.Time
+	c     chan time.Time
+}
+
+func (t *manualTicker) C() <-chan time.Time { return t.c }
+
+func (t *manualTicker) Stop() {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, other := range c.tickers {
+		if other == t {
+			c.tickers = append(c.tickers[:i], c.tickers[i+1:]...)
+			return
+		}
+	}
+}
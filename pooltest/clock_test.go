@@ -0,0 +1,52 @@
+package pooltest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ongniud/pool"
+)
+
+// TestManualClock tests that tickers fire only when the clock is advanced.
+func TestManualClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(start)
+	ticker := c.NewTicker(time.Minute)
+
+	c.Advance(30 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Error("Expected no tick before the interval passed")
+	default:
+	}
+	c.Advance(30 * time.Second)
+	select {
+	case tick := <-ticker.C():
+		if !tick.Equal(start.Add(time.Minute)) {
+			t.Errorf("Expected a tick at 1m, got %v", tick)
+		}
+	default:
+		t.Error("Expected a tick once the interval passed")
+	}
+	ticker.Stop()
+	if n := c.Tickers(); n != 0 {
+		t.Errorf("Expected the ticker to be stopped, got %d running", n)
+	}
+}
+
+// TestManualClockQuarantine tests that advancing the clock releases quarantined objects.
+func TestManualClockQuarantine(t *testing.T) {
+	c := NewManualClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	p := pool.NewPool(func() interface{} {
+		return new(int)
+	}, pool.WithClock(c), pool.WithQuarantine(time.Minute), pool.WithDeterministicSharding(1))
+	p.Put(new(int))
+
+	c.Advance(2 * time.Minute)
+	for i := 0; p.Stats().Idle != 1 && i < 100; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if n := p.Stats().Idle; n != 1 {
+		t.Errorf("Expected the quarantined object to be released, got %d idle", n)
+	}
+}
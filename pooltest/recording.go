@@ -0,0 +1,202 @@
+// Package pooltest provides test doubles for code built on the pool package.
+package pooltest
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ongniud/pool"
+)
+
+// Op is the kind of a recorded call.
+type Op string
+
+const (
+	// OpGet records a Get
+	OpGet Op = "get"
+	// OpPut records a Put
+	OpPut Op = "put"
+	// OpClear records a Clear
+	OpClear Op = "clear"
+)
+
+// Event is a call recorded by a RecordingPool.
+type Event struct {
+	Op Op
+	// Object is the object handed out or returned, nil for Clear
+	Object interface{}
+	// Caller is the file:line of the code that called the pool
+	Caller string
+}
+
+// String returns a readable form of the event.
+func (e Event) String() string {
+	if e.Op == OpClear {
+		return fmt.Sprintf("%s at %s", e.Op, e.Caller)
+	}
+	return fmt.Sprintf("%s %T at %s", e.Op, e.Object, e.Caller)
+}
+
+// TB is the subset of testing.TB used by the assertions.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// RecordingPool is a Pooler that records every call with its caller before
+// passing it to the wrapped Pooler, so tests can assert on pooling discipline.
+// It is safe for concurrent use.
+type RecordingPool struct {
+	pool pool.Pooler
+
+	mu          sync.Mutex
+	events      []Event
+	outstanding map[interface{}]int
+	returned    map[interface{}]bool
+	doublePuts  []Event
+}
+
+// NewRecordingPool creates a RecordingPool wrapping p.
+func NewRecordingPool(p pool.Pooler) *RecordingPool {
+	return &RecordingPool{
+		pool:        p,
+		outstanding: make(map[interface{}]int),
+		returned:    make(map[interface{}]bool),
+	}
+}
+
+// Get retrieves an object from the wrapped Pooler and records it.
+func (r *RecordingPool) Get() interface{} {
+	obj := r.pool.Get()
+	e := Event{Op: OpGet, Object: obj, Caller: caller()}
+	r.mu.Lock()
+	if key, ok := identity(obj); ok {
+		r.outstanding[key] = len(r.events)
+		delete(r.returned, key)
+	}
+	r.events = append(r.events, e)
+	r.mu.Unlock()
+	return obj
+}
+
+// Put records obj and returns it to the wrapped Pooler.
+// Returning an object that was already returned since it was last handed out
+// is recorded as a double Put and not passed on.
+func (r *RecordingPool) Put(obj interface{}) {
+	e := Event{Op: OpPut, Object: obj, Caller: caller()}
+	r.mu.Lock()
+	r.events = append(r.events, e)
+	if key, ok := identity(obj); ok {
+		if r.returned[key] {
+			r.doublePuts = append(r.doublePuts, e)
+			r.mu.Unlock()
+			return
+		}
+		delete(r.outstanding, key)
+		r.returned[key] = true
+	}
+	r.mu.Unlock()
+	r.pool.Put(obj)
+}
+
+// Clear records the call and clears the wrapped Pooler.
+func (r *RecordingPool) Clear() {
+	r.mu.Lock()
+	r.events = append(r.events, Event{Op: OpClear, Caller: caller()})
+	r.mu.Unlock()
+	r.pool.Clear()
+}
+
+// Events returns the recorded calls in order.
+func (r *RecordingPool) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// Outstanding returns the Gets whose objects have not been returned, in order.
+func (r *RecordingPool) Outstanding() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	idx := make([]int, 0, len(r.outstanding))
+	for _, i := range r.outstanding {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	out := make([]Event, len(idx))
+	for n, i := range idx {
+		out[n] = r.events[i]
+	}
+	return out
+}
+
+// DoublePuts returns the Puts of objects that had already been returned.
+func (r *RecordingPool) DoublePuts() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.doublePuts...)
+}
+
+// Reset forgets all recorded calls.
+func (r *RecordingPool) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+	r.outstanding = make(map[interface{}]int)
+	r.returned = make(map[interface{}]bool)
+	r.doublePuts = nil
+}
+
+// AssertAllReturned fails t for every object handed out and not returned.
+func (r *RecordingPool) AssertAllReturned(t TB) {
+	t.Helper()
+	for _, e := range r.Outstanding() {
+		t.Errorf("pooltest: object not returned: %s", e)
+	}
+}
+
+// AssertNoDoublePuts fails t for every object returned twice.
+func (r *RecordingPool) AssertNoDoublePuts(t TB) {
+	t.Helper()
+	for _, e := range r.DoublePuts() {
+		t.Errorf("pooltest: object returned twice: %s", e)
+	}
+}
+
+// identity returns a map key identifying obj: its address for reference kinds
+// and the value itself for other comparable values.
+func identity(obj interface{}) (interface{}, bool) {
+	if obj == nil {
+		return nil, false
+	}
+	v := reflect.ValueOf(obj)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return v.Pointer(), true
+	case reflect.Slice:
+		if v.Cap() == 0 {
+			return nil, false
+		}
+		return v.Pointer(), true
+	}
+	if v.Comparable() {
+		return obj, true
+	}
+	return nil, false
+}
+
+// caller returns the file:line of the caller of the RecordingPool method.
+func caller() string {
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		file = file[i+1:]
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
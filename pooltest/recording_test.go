@@ -0,0 +1,80 @@
+package pooltest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ongniud/pool"
+)
+
+// fakeTB records the failures of an assertion.
+type fakeTB struct {
+	errors []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+// newRecording returns a RecordingPool wrapping a pool of *int.
+func newRecording() *RecordingPool {
+	return NewRecordingPool(pool.NewPool(func() interface{} {
+		return new(int)
+	}))
+}
+
+// TestRecordingPool tests that calls are recorded with their callers.
+func TestRecordingPool(t *testing.T) {
+	r := newRecording()
+	obj := r.Get()
+	r.Put(obj)
+	r.Clear()
+
+	events := r.Events()
+	if len(events) != 3 || events[0].Op != OpGet || events[1].Op != OpPut || events[2].Op != OpClear {
+		t.Fatalf("Expected get, put and clear, got %v", events)
+	}
+	if events[0].Object != obj || !strings.HasPrefix(events[0].Caller, "recording_test.go:") {
+		t.Errorf("Expected the object and caller to be recorded, got %v", events[0])
+	}
+	r.AssertAllReturned(t)
+	r.AssertNoDoublePuts(t)
+}
+
+// TestAssertAllReturned tests that objects that are not returned are reported.
+func TestAssertAllReturned(t *testing.T) {
+	r := newRecording()
+	r.Put(r.Get())
+	kept := r.Get()
+
+	var tb fakeTB
+	r.AssertAllReturned(&tb)
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "not returned") {
+		t.Errorf("Expected 1 object not returned, got %v", tb.errors)
+	}
+	if out := r.Outstanding(); len(out) != 1 || out[0].Object != kept {
+		t.Errorf("Expected the kept object to be outstanding, got %v", out)
+	}
+}
+
+// TestAssertNoDoublePuts tests that objects returned twice are reported and not passed on.
+func TestAssertNoDoublePuts(t *testing.T) {
+	r := newRecording()
+	obj := r.Get()
+	r.Put(obj)
+	r.Put(obj)
+
+	var tb fakeTB
+	r.AssertNoDoublePuts(&tb)
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "returned twice") {
+		t.Errorf("Expected 1 double put, got %v", tb.errors)
+	}
+
+	r.Reset()
+	if len(r.Events()) != 0 || len(r.DoublePuts()) != 0 {
+		t.Error("Expected Reset to forget all calls")
+	}
+}
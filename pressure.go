@@ -0,0 +1,171 @@
+package pool
+
+import (
+	"math"
+	"runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Default interval at which the memory monitor samples memory usage
+	defaultPressureInterval = time.Second
+	// Default fraction of GOMEMLIMIT above which memory is under pressure
+	defaultPressureFraction = 0.9
+)
+
+// MemoryMonitorConfig configures a MemoryMonitor.
+type MemoryMonitorConfig struct {
+	// Interval at which memory usage is sampled, one second if zero
+	Interval time.Duration
+	// Fraction of GOMEMLIMIT above which memory is under pressure, 0.9 if zero
+	Fraction float64
+	// Threshold in bytes above which memory is under pressure; overrides
+	// Fraction if set, and is required when GOMEMLIMIT is not set
+	Threshold uint64
+}
+
+// MemoryMonitor sheds pooled objects under memory pressure.
+// It periodically compares the memory used by the Go runtime with a threshold
+// derived from GOMEMLIMIT; once it is exceeded, the monitored pools destroy
+// their idle objects and drop every returned object, until usage falls back
+// below the threshold.
+type MemoryMonitor struct {
+	cfg   MemoryMonitorConfig
+	usage func() (used, limit uint64)
+
+	mu       sync.Mutex
+	pools    []*Pool
+	pressure bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// StartMemoryMonitor starts monitoring memory usage on behalf of pools.
+// The monitor must be stopped with Stop.
+func StartMemoryMonitor(cfg MemoryMonitorConfig, pools ...*Pool) *MemoryMonitor {
+	m := newMemoryMonitor(cfg, readMemoryUsage, pools)
+	m.wg.Add(1)
+	go m.loop()
+	return m
+}
+
+// newMemoryMonitor creates a stopped monitor reading memory usage with usage.
+func newMemoryMonitor(cfg MemoryMonitorConfig, usage func() (used, limit uint64), pools []*Pool) *MemoryMonitor {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultPressureInterval
+	}
+	if cfg.Fraction <= 0 {
+		cfg.Fraction = defaultPressureFraction
+	}
+	return &MemoryMonitor{
+		cfg:   cfg,
+		usage: usage,
+		pools: pools,
+		stop:  make(chan struct{}),
+	}
+}
+
+// Add adds a pool to the monitored pools.
+func (m *MemoryMonitor) Add(p *Pool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pools = append(m.pools, p)
+	if m.pressure {
+		p.setPressure(true)
+	}
+}
+
+// UnderPressure reports whether memory was under pressure at the last sample.
+func (m *MemoryMonitor) UnderPressure() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pressure
+}
+
+// Stop stops the monitor and restores normal behavior of the pools.
+func (m *MemoryMonitor) Stop() {
+	close(m.stop)
+	m.wg.Wait()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range m.pools {
+		p.setPressure(false)
+	}
+	m.pressure = false
+}
+
+// loop samples memory usage until the monitor is stopped.
+func (m *MemoryMonitor) loop() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check samples memory usage and switches the pools in or out of pressure mode.
+func (m *MemoryMonitor) check() {
+	used, limit := m.usage()
+	threshold := m.cfg.Threshold
+	if threshold == 0 {
+		if limit == math.MaxInt64 {
+			// GOMEMLIMIT is not set
+			return
+		}
+		threshold = uint64(float64(limit) * m.cfg.Fraction)
+	}
+	pressure := used > threshold
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if pressure == m.pressure {
+		return
+	}
+	m.pressure = pressure
+	for _, p := range m.pools {
+		p.setPressure(pressure)
+	}
+}
+
+// setPressure switches the pool in or out of pressure mode.
+// Entering pressure mode destroys the idle objects.
+func (p *Pool) setPressure(on bool) {
+	if !on {
+		atomic.StoreInt32(&p.pressure, 0)
+		return
+	}
+	atomic.StoreInt32(&p.pressure, 1)
+	cfg := p.config()
+	for i := range p.shards {
+		for _, e := range p.takeIdle(i) {
+			p.destroy(e.obj, cfg)
+		}
+	}
+}
+
+// readMemoryUsage returns the memory used by the Go runtime and GOMEMLIMIT,
+// measured the way the runtime enforces the limit.
+func readMemoryUsage() (used, limit uint64) {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+		{Name: "/gc/gomemlimit:bytes"},
+	}
+	metrics.Read(samples)
+	for _, s := range samples {
+		if s.Value.Kind() != metrics.KindUint64 {
+			return 0, math.MaxInt64
+		}
+	}
+	used = samples[0].Value.Uint64() - samples[1].Value.Uint64()
+	return used, samples[2].Value.Uint64()
+}
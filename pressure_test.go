@@ -0,0 +1,69 @@
+package pool
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMemoryMonitor tests that pools shed objects under pressure and recover afterwards.
+func TestMemoryMonitor(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	p.Put(new(int))
+
+	var used uint64 = 50
+	m := newMemoryMonitor(MemoryMonitorConfig{}, func() (uint64, uint64) {
+		return used, 100
+	}, []*Pool{p})
+
+	m.check()
+	if m.UnderPressure() || idleCount(p) != 1 {
+		t.Error("Expected no pressure below the threshold")
+	}
+
+	used = 95
+	m.check()
+	if !m.UnderPressure() || idleCount(p) != 0 {
+		t.Error("Expected idle objects to be shed under pressure")
+	}
+	p.Put(new(int))
+	if n := idleCount(p); n != 0 {
+		t.Errorf("Expected Puts to be dropped under pressure, got %d idle", n)
+	}
+
+	used = 10
+	m.check()
+	p.Put(new(int))
+	if m.UnderPressure() || idleCount(p) != 1 {
+		t.Error("Expected normal behavior after the pressure ended")
+	}
+}
+
+// TestMemoryMonitorNoLimit tests that the monitor is inactive without GOMEMLIMIT or threshold.
+func TestMemoryMonitorNoLimit(t *testing.T) {
+	m := newMemoryMonitor(MemoryMonitorConfig{}, func() (uint64, uint64) {
+		return math.MaxInt64 - 1, math.MaxInt64
+	}, nil)
+	m.check()
+	if m.UnderPressure() {
+		t.Error("Expected no pressure without a limit")
+	}
+
+	m = newMemoryMonitor(MemoryMonitorConfig{Threshold: 10}, func() (uint64, uint64) {
+		return 20, math.MaxInt64
+	}, nil)
+	m.check()
+	if !m.UnderPressure() {
+		t.Error("Expected the absolute threshold to apply without a limit")
+	}
+}
+
+// TestStartMemoryMonitor tests that a started monitor can be stopped.
+func TestStartMemoryMonitor(t *testing.T) {
+	m := StartMemoryMonitor(MemoryMonitorConfig{Interval: 1})
+	m.Stop()
+	if used, _ := readMemoryUsage(); used == 0 {
+		t.Error("Expected non-zero memory usage")
+	}
+}
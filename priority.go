@@ -0,0 +1,50 @@
+package pool
+
+// PutPriority returns an object to the pool like Put, tagged with a priority
+// class, e.g. higher for larger or warmer buffers. With the PriorityPolicy
+// eviction policy, Gets hand out the highest-priority idle objects first and
+// full shards evict the lowest-priority ones. Put returns objects with
+// priority 0.
+func (p *Pool) PutPriority(obj interface{}, priority int) {
+	p.putHooked(obj, putOptions{priority: priority})
+}
+
+// PriorityPolicy reuses the idle object with the highest priority, see
+// PutPriority, the most recently returned one among equals. When a shard is
+// full, it evicts the objects with the lowest priority, the least recently
+// returned ones among equals.
+var PriorityPolicy EvictionPolicy = priorityPolicy{}
+
+// priorityPolicy implements PriorityPolicy.
+type priorityPolicy struct{}
+
+func (priorityPolicy) OnPut(info ObjectInfo) {}
+
+func (priorityPolicy) OnGet(idle IdleList) int {
+	best := idle.Len() - 1
+	for i := best - 1; i >= 0; i-- {
+		if idle.At(i).Priority > idle.At(best).Priority {
+			best = i
+		}
+	}
+	return best
+}
+
+func (priorityPolicy) SelectVictims(idle IdleList, n int) []int {
+	if n > idle.Len() {
+		n = idle.Len()
+	}
+	victims := make([]int, 0, n)
+	taken := make([]bool, idle.Len())
+	for len(victims) < n {
+		worst := -1
+		for i := 0; i < idle.Len(); i++ {
+			if !taken[i] && (worst < 0 || idle.At(i).Priority < idle.At(worst).Priority) {
+				worst = i
+			}
+		}
+		taken[worst] = true
+		victims = append(victims, worst)
+	}
+	return victims
+}
@@ -0,0 +1,47 @@
+package pool
+
+import "testing"
+
+// TestPriorityPolicyGet tests that Get hands out the highest-priority object first.
+func TestPriorityPolicyGet(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithEvictionPolicy(PriorityPolicy)))
+	id := busiestShard(p.Stats())
+	low, high, mid := new(int), new(int), new(int)
+	for _, e := range []entry{{obj: low, priority: 1}, {obj: high, priority: 3}, {obj: mid, priority: 2}} {
+		p.shards[id].push(e, shardCap, nil)
+	}
+
+	cfg := p.config()
+	for _, want := range []*int{high, mid, low} {
+		if e, _ := p.shards[id].pop(cfg); e.obj != want {
+			t.Errorf("Expected the next highest priority, got %d", e.priority)
+		}
+	}
+}
+
+// TestPriorityPolicyEvict tests that full shards evict the lowest-priority objects.
+func TestPriorityPolicyEvict(t *testing.T) {
+	idle := idleList{{priority: 2}, {priority: 1}, {priority: 3}, {priority: 1}}
+	victims := PriorityPolicy.SelectVictims(idle, 3)
+	if len(victims) != 3 || victims[0] != 1 || victims[1] != 3 || victims[2] != 0 {
+		t.Errorf("Expected victims [1 3 0], got %v", victims)
+	}
+}
+
+// TestPutPriority tests that PutPriority records the priority of the object.
+func TestPutPriority(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	p.PutPriority(p.Get(), 5)
+
+	var got []int
+	p.Inspect(func(info ObjectInfo) {
+		got = append(got, info.Priority)
+	})
+	if len(got) != 1 || got[0] != 5 {
+		t.Errorf("Expected one object with priority 5, got %v", got)
+	}
+}
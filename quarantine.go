@@ -0,0 +1,88 @@
+package pool
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// quarantine holds returned entries aside until they may be reused.
+// Entries are ordered by release time.
+type quarantine struct {
+	mu      sync.Mutex
+	entries []quarantined
+}
+
+// quarantined is an entry held in quarantine until the given time.
+type quarantined struct {
+	entry
+	until int64
+}
+
+// add holds e in quarantine until the given time.
+func (q *quarantine) add(e entry, until int64) {
+	q.mu.Lock()
+	q.entries = append(q.entries, quarantined{entry: e, until: until})
+	q.mu.Unlock()
+}
+
+// expired removes and returns the entries held until now or earlier.
+func (q *quarantine) expired(now int64) []entry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for n < len(q.entries) && q.entries[n].until <= now {
+		n++
+	}
+	return q.takeLocked(n)
+}
+
+// takeAll removes and returns all entries.
+func (q *quarantine) takeAll() []entry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.takeLocked(len(q.entries))
+}
+
+// takeLocked removes and returns the n oldest entries.
+// q.mu must be held.
+func (q *quarantine) takeLocked(n int) []entry {
+	if n == 0 {
+		return nil
+	}
+	taken := make([]entry, n)
+	for i := range taken {
+		taken[i] = q.entries[i].entry
+	}
+	rest := copy(q.entries, q.entries[n:])
+	clear(q.entries[rest:])
+	q.entries = q.entries[:rest]
+	return taken
+}
+
+// len returns the number of entries in quarantine.
+func (q *quarantine) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries)
+}
+
+// startQuarantine starts releasing quarantined objects once their delay, d
+// at the time they were returned, has passed.
+func (p *Pool) startQuarantine(d time.Duration) {
+	p.runTask(max(d/2, time.Millisecond), func(p *Pool) {
+		p.releaseQuarantine(p.config().clock.Now())
+	})
+}
+
+// releaseQuarantine moves the quarantined objects whose delay passed before now into the
+// shards. Objects the shards do not retain are destroyed, since their Put
+// already reported them as retained.
+func (p *Pool) releaseQuarantine(now time.Time) {
+	cfg := p.config()
+	for _, e := range p.quarantine.expired(now.UnixNano()) {
+		if atomic.LoadInt32(&p.state) != stateOpen || !p.restore(e, cfg) {
+			p.destroy(e.obj, cfg)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestQuarantine tests that returned objects are only reused after the delay.
+func TestQuarantine(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithQuarantine(time.Hour)))
+	obj := new(int)
+
+	if !p.PutCheck(obj) {
+		t.Error("Expected the quarantined object to be retained")
+	}
+	if got := p.Get(); got == obj {
+		t.Error("Expected the quarantined object not to be reused")
+	}
+	p.releaseQuarantine(time.Now())
+	if n := idleCount(p); n != 0 {
+		t.Errorf("Expected the object to stay in quarantine, got %d idle", n)
+	}
+	p.releaseQuarantine(time.Now().Add(2 * time.Hour))
+	if n := p.quarantine.len(); n != 0 {
+		t.Errorf("Expected the quarantine to be empty, got %d", n)
+	}
+	if got := p.Get(); got != obj {
+		t.Error("Expected the released object to be reused")
+	}
+}
+
+// TestQuarantineDrain tests that Drain destroys quarantined objects.
+func TestQuarantineDrain(t *testing.T) {
+	destroyed := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithQuarantine(time.Hour), WithDestructor(func(obj interface{}) {
+		destroyed++
+	})))
+	p.Put(p.Get())
+
+	if err := p.Drain(context.Background()); err != nil {
+		t.Fatalf("Expected Drain to succeed, got %v", err)
+	}
+	if destroyed != 1 {
+		t.Errorf("Expected the quarantined object to be destroyed, got %d", destroyed)
+	}
+}
+
+// TestQuarantineShards tests that released objects return to the shards of their Puts.
+func TestQuarantineShards(t *testing.T) {
+	destroyed := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithQuarantine(time.Hour), WithDeterministicSharding(1), WithDestructor(func(obj interface{}) {
+		destroyed++
+	})))
+	for i := 0; i < 1000; i++ {
+		p.Put(new(int))
+	}
+	// Release from what would be a single shard without the recorded ones
+	p.sequence = nil
+	p.releaseQuarantine(time.Now().Add(2 * time.Hour))
+	if n := idleCount(p); n != 1000 || destroyed != 0 {
+		t.Errorf("Expected 1000 released idle objects, got %d and %d destroyed", n, destroyed)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package pool
+
+// Building with -race enables race chaos for every pool, see raceChaos
+const raceEnabled = true
@@ -0,0 +1,99 @@
+package pool
+
+import "sync/atomic"
+
+// Background tasks of a pool, see startTasks
+const (
+	taskGCTrim uint32 = 1 << iota
+	taskAutoTune
+	taskHotCold
+	taskQuarantine
+	taskSoftCap
+	taskRetention
+	taskRefresh
+)
+
+// Reconfigure applies opts to the running pool, e.g. from a configuration
+// reload. Gets and Puts in flight finish with the previous settings.
+//
+// Lowering the shard capacity trims the objects above it, steal settings and
+// in-use limits take effect immediately, and enabling GC trimming, auto-tuning,
+// the hot tier, the quarantine, the soft capacity, the retention target or the
+// refresh starts their background tasks. The number of shards is fixed,
+// so no resharding is needed. Options that change which objects the pool
+// accounts, namely WithGroup, WithDeterministicSharding, WithSizer and
+// enabling or disabling WithMaxInUse, fail with ErrNotReconfigurable and
+// nothing is changed.
+func (p *Pool) Reconfigure(opts ...Option) error {
+	old := p.config()
+	next := *old
+	for _, opt := range opts {
+		opt(&next)
+	}
+	if next.group != old.group || next.shardSeed != old.shardSeed ||
+		(next.maxInUse > 0) != (old.maxInUse > 0) ||
+		(next.sizer == nil) != (old.sizer == nil) {
+		return ErrNotReconfigurable
+	}
+
+	var prev config
+	p.updateConfig(func(c *config) {
+		prev = *c
+		for _, opt := range opts {
+			opt(c)
+		}
+		if c.autoTune != nil {
+			c.shardCap = min(max(c.shardCap, c.autoTune.MinCap), c.autoTune.MaxCap)
+		}
+	})
+	cur := p.config()
+	if cur.maxInUse != prev.maxInUse {
+		p.inUse.resize(int64(cur.maxInUse))
+	}
+	if cur.stealShardCnt != prev.stealShardCnt {
+		atomic.StoreInt32(&p.steal.n, int32(cur.stealShardCnt))
+	}
+	if cur.shardCap < prev.shardCap {
+		p.Trim(cur.shardCap)
+	}
+	p.startTasks(cur)
+	return nil
+}
+
+// startTasks starts the background tasks enabled by cfg that are not running yet.
+func (p *Pool) startTasks(cfg *config) {
+	if cfg.gcTrimFraction > 0 && p.startTask(taskGCTrim) {
+		p.startGCTrim()
+	}
+	if cfg.autoTune != nil && p.startTask(taskAutoTune) {
+		p.startAutoTune(cfg.autoTune.Interval)
+	}
+	if cfg.hotCap > 0 && p.startTask(taskHotCold) {
+		p.startHotCold(cfg.hotAge)
+	}
+	if cfg.quarantine > 0 && p.startTask(taskQuarantine) {
+		p.startQuarantine(cfg.quarantine)
+	}
+	if cfg.softCap > 0 && p.startTask(taskSoftCap) {
+		p.startSoftCap(cfg.softTTL)
+	}
+	if cfg.retentionTarget > 0 && p.startTask(taskRetention) {
+		p.startRetention()
+	}
+	if cfg.refreshInterval > 0 && cfg.refresh != nil && p.startTask(taskRefresh) {
+		p.startRefresh(cfg.refreshInterval)
+	}
+}
+
+// startTask marks task as running and reports whether it was not running before.
+func (p *Pool) startTask(task uint32) bool {
+	for {
+		tasks := atomic.LoadUint32(&p.tasks)
+		if tasks&task != 0 {
+			return false
+		}
+		if atomic.CompareAndSwapUint32(&p.tasks, tasks, tasks|task) {
+			return true
+		}
+	}
+}
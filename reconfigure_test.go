@@ -0,0 +1,51 @@
+package pool
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// TestReconfigure tests that Reconfigure applies capacity and steal settings.
+func TestReconfigure(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	for i := 0; i < 5; i++ {
+		p.Put(new(int))
+	}
+
+	if err := p.Reconfigure(WithShardCap(2), WithStealShardCount(len(p.shards)-1)); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if n := idleCount(p); n != 2 {
+		t.Errorf("Expected the lower capacity to trim the pool, got %d idle", n)
+	}
+	if cfg := p.config(); cfg.shardCap != 2 || cfg.stealShardCnt != len(p.shards)-1 {
+		t.Errorf("Unexpected config %d/%d", cfg.shardCap, cfg.stealShardCnt)
+	}
+	if n := atomic.LoadInt32(&p.steal.n); int(n) != len(p.shards)-1 {
+		t.Errorf("Expected the steal budget to follow, got %d", n)
+	}
+
+	if err := p.Reconfigure(WithHotCold(4, 0)); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if atomic.LoadUint32(&p.tasks)&taskHotCold == 0 {
+		t.Error("Expected enabling the hot tier to start its task")
+	}
+}
+
+// TestReconfigureRejected tests that creation-time options are rejected.
+func TestReconfigureRejected(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+	for _, opt := range []Option{WithGroup(NewGroup(1), 1), WithMaxInUse(1, false), WithDeterministicSharding(1)} {
+		if err := p.Reconfigure(WithShardCap(1), opt); err != ErrNotReconfigurable {
+			t.Errorf("Expected ErrNotReconfigurable, got %v", err)
+		}
+	}
+	if cfg := p.config(); cfg.shardCap != shardCap {
+		t.Errorf("Expected a rejected Reconfigure to change nothing, got capacity %d", cfg.shardCap)
+	}
+}
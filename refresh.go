@@ -0,0 +1,47 @@
+package pool
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// startRefresh starts refreshing the idle objects once per interval.
+func (p *Pool) startRefresh(interval time.Duration) {
+	p.runTask(interval, func(p *Pool) {
+		p.refreshIdle()
+	})
+}
+
+// refreshIdle runs the refresh function of the pool on its idle objects, one
+// at a time, and returns the number of destroyed objects. Objects that pass
+// are returned to their shard right away; those that fail, and those the pool
+// no longer retains, are destroyed.
+func (p *Pool) refreshIdle() int {
+	cfg := p.config()
+	if cfg.refresh == nil {
+		return 0
+	}
+	failed := 0
+	for i := range p.shards {
+		p.visitIdle(i, func(e entry) {
+			if err := cfg.refresh(e.obj); err != nil {
+				failed++
+				if cfg.logger != nil {
+					p.log(cfg, cfg.levels().Evictions, "pool refresh failed",
+						slog.String("type", fmt.Sprintf("%T", e.obj)), slog.Any("error", err))
+				}
+				p.destroy(e.obj, cfg)
+				return
+			}
+			// The refresh may have written to the object
+			e.poisoned = false
+			e.hint = shardHint{key: uint64(i), set: true}
+			if atomic.LoadInt32(&p.state) != stateOpen || !p.restore(e, cfg) {
+				p.destroy(e.obj, cfg)
+			}
+		})
+	}
+	return failed
+}
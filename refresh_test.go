@@ -0,0 +1,94 @@
+package pool
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// refreshConn is an idle connection the refresh can find broken.
+type refreshConn struct {
+	id     int
+	broken bool
+}
+
+// TestRefresh tests that objects failing the refresh are destroyed.
+func TestRefresh(t *testing.T) {
+	var refreshed, destroyed int32
+	p := predictable(NewPool(func() interface{} {
+		return &refreshConn{}
+	}, WithRefresh(time.Hour, func(obj interface{}) error {
+		atomic.AddInt32(&refreshed, 1)
+		if obj.(*refreshConn).broken {
+			return errors.New("broken")
+		}
+		return nil
+	}), WithDestructor(func(obj interface{}) {
+		atomic.AddInt32(&destroyed, 1)
+	})))
+
+	for i := 0; i < 6; i++ {
+		putShard(p, uint64(i), &refreshConn{id: i, broken: i%3 == 0})
+	}
+	if n := p.refreshIdle(); n != 2 {
+		t.Errorf("Expected 2 failed objects, got %d", n)
+	}
+	if refreshed != 6 || destroyed != 2 {
+		t.Errorf("Expected 6 refreshed and 2 destroyed objects, got %d and %d", refreshed, destroyed)
+	}
+	if n := idleCount(p); n != 4 {
+		t.Errorf("Expected 4 healthy idle objects, got %d", n)
+	}
+	if n := p.shards[1].idle(); n != 1 {
+		t.Errorf("Expected healthy objects to return to their shard, got %d in shard 1", n)
+	}
+}
+
+// TestRefreshBackground tests that the refresh runs periodically.
+func TestRefreshBackground(t *testing.T) {
+	var refreshed int32
+	p := predictable(NewPool(func() interface{} {
+		return &refreshConn{}
+	}, WithRefresh(5*time.Millisecond, func(obj interface{}) error {
+		atomic.AddInt32(&refreshed, 1)
+		return nil
+	})))
+	putShard(p, 0, &refreshConn{})
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&refreshed) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&refreshed); n < 2 {
+		t.Errorf("Expected the idle object to be refreshed repeatedly, got %d refreshes", n)
+	}
+}
+
+// TestRefreshOneAtATime tests that the shard keeps its other objects while one is refreshed.
+func TestRefreshOneAtATime(t *testing.T) {
+	var p *Pool
+	seen := make(map[int]int)
+	p = predictable(NewPool(func() interface{} {
+		return &refreshConn{}
+	}, WithRefresh(time.Hour, func(obj interface{}) error {
+		seen[obj.(*refreshConn).id]++
+		if n := p.shards[0].idle(); n != 3 {
+			t.Errorf("Expected the other 3 objects to stay idle during a refresh, got %d", n)
+		}
+		return nil
+	})))
+	for i := 0; i < 4; i++ {
+		putShard(p, 0, &refreshConn{id: i})
+	}
+
+	p.refreshIdle()
+	if len(seen) != 4 {
+		t.Errorf("Expected all 4 objects to be refreshed, got %v", seen)
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("Expected object %d to be refreshed once, got %d", id, n)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package pool
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Registry maps object types to pools, giving an application a single place to
+// pool many object types.
+type Registry struct {
+	mu    sync.RWMutex
+	pools map[reflect.Type]*Pool
+}
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		pools: make(map[reflect.Type]*Pool),
+	}
+}
+
+// Register registers a pool for type T in r, using fn to create new objects.
+// Registering a type twice replaces its pool. opts customize the pool.
+func Register[T any](r *Registry, fn func() T, opts ...Option) {
+	if fn == nil {
+		panic("newFunc cannot be nil")
+	}
+	p := NewPool(func() interface{} {
+		return fn()
+	}, opts...)
+	r.mu.Lock()
+	r.pools[typeOf[T]()] = p
+	r.mu.Unlock()
+}
+
+// Get retrieves an object of type T from r.
+// It panics if T has not been registered.
+func Get[T any](r *Registry) T {
+	return r.mustPool(typeOf[T]()).Get().(T)
+}
+
+// Put returns an object of type T to r.
+// It panics if T has not been registered.
+func Put[T any](r *Registry, obj T) {
+	r.mustPool(typeOf[T]()).Put(obj)
+}
+
+// Lookup returns the pool registered for typ, if any.
+func (r *Registry) Lookup(typ reflect.Type) (*Pool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.pools[typ]
+	return p, ok
+}
+
+// Each calls fn for every registered type and its pool.
+func (r *Registry) Each(fn func(typ reflect.Type, p *Pool)) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for typ, p := range r.pools {
+		fn(typ, p)
+	}
+}
+
+// Close clears all registered pools and unregisters them.
+func (r *Registry) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for typ, p := range r.pools {
+		p.Clear()
+		delete(r.pools, typ)
+	}
+}
+
+// mustPool returns the pool registered for typ or panics.
+func (r *Registry) mustPool(typ reflect.Type) *Pool {
+	p, ok := r.Lookup(typ)
+	if !ok {
+		panic("pool: type " + typ.String() + " is not registered")
+	}
+	return p
+}
+
+// typeOf returns the reflect.Type of T, including interface types.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,49 @@
+package pool
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// TestRegistry tests typed Get and Put through a Registry.
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	Register(r, func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+	Register(r, func() []int {
+		return make([]int, 0, 8)
+	})
+
+	buf := Get[*bytes.Buffer](r)
+	buf.WriteString("x")
+	Put(r, buf)
+
+	if s := Get[[]int](r); cap(s) != 8 {
+		t.Errorf("Expected slice with capacity 8, got %d", cap(s))
+	}
+
+	n := 0
+	r.Each(func(typ reflect.Type, p *Pool) {
+		n++
+	})
+	if n != 2 {
+		t.Errorf("Expected 2 registered types, got %d", n)
+	}
+
+	r.Close()
+	if _, ok := r.Lookup(reflect.TypeOf(buf)); ok {
+		t.Error("Expected no pools after Close")
+	}
+}
+
+// TestRegistryUnregistered tests that getting an unregistered type panics.
+func TestRegistryUnregistered(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for unregistered type")
+		}
+	}()
+	Get[*int](NewRegistry())
+}
@@ -0,0 +1,190 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Number of objects ResourcePool.Get checks before giving up with ErrUnhealthy
+const healthCheckAttempts = 3
+
+// ResourcePool manages stateful resources such as connections on top of a Pool.
+// Objects are health checked on Get (and optionally periodically while idle),
+// unhealthy or rejected objects are destroyed, and objects handed out are counted.
+type ResourcePool struct {
+	pool          *Pool
+	healthCheck   func(obj interface{}) bool
+	destroy       func(obj interface{})
+	checkInterval time.Duration
+
+	inUse     int64
+	peakInUse int64
+	closed    int32
+	stop      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// ResourceOption configures a ResourcePool.
+type ResourceOption func(rp *ResourcePool)
+
+// WithHealthCheck sets the function reporting whether an object is still usable.
+// It is run on every Get and by the background checker.
+func WithHealthCheck(fn func(obj interface{}) bool) ResourceOption {
+	return func(rp *ResourcePool) {
+		rp.healthCheck = fn
+	}
+}
+
+// WithHealthCheckInterval enables a background checker that health checks
+// idle objects every interval and destroys the unhealthy ones.
+func WithHealthCheckInterval(interval time.Duration) ResourceOption {
+	return func(rp *ResourcePool) {
+		rp.checkInterval = interval
+	}
+}
+
+// WithDestroy sets the function releasing an object that leaves the pool for good,
+// e.g. closing a connection.
+func WithDestroy(fn func(obj interface{})) ResourceOption {
+	return func(rp *ResourcePool) {
+		rp.destroy = fn
+	}
+}
+
+// NewResourcePool creates a new resource pool on top of p.
+func NewResourcePool(p *Pool, opts ...ResourceOption) *ResourcePool {
+	if p == nil {
+		panic("pool cannot be nil")
+	}
+	rp := &ResourcePool{
+		pool:    p,
+		destroy: func(obj interface{}) {},
+		stop:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(rp)
+	}
+	if rp.healthCheck != nil && rp.checkInterval > 0 {
+		rp.wg.Add(1)
+		go rp.checkLoop()
+	}
+	return rp
+}
+
+// Get retrieves a healthy object from the pool.
+// Unhealthy objects are destroyed; after healthCheckAttempts unhealthy objects
+// in a row it returns ErrUnhealthy.
+func (rp *ResourcePool) Get(ctx context.Context) (interface{}, error) {
+	for i := 0; i < healthCheckAttempts; i++ {
+		if atomic.LoadInt32(&rp.closed) == 1 {
+			return nil, ErrPoolClosed
+		}
+		obj, err := rp.pool.GetContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if rp.healthCheck != nil && !rp.healthCheck(obj) {
+			rp.pool.Discard(obj)
+			rp.destroy(obj)
+			continue
+		}
+		rp.checkout()
+		return obj, nil
+	}
+	return nil, ErrUnhealthy
+}
+
+// Put returns an object to the pool.
+// If the pool declines the object or is closed, the object is destroyed.
+func (rp *ResourcePool) Put(obj interface{}) {
+	if obj == nil {
+		return
+	}
+	atomic.AddInt64(&rp.inUse, -1)
+	if atomic.LoadInt32(&rp.closed) == 1 {
+		rp.pool.Discard(obj)
+		rp.destroy(obj)
+		return
+	}
+	if !rp.pool.put(obj) {
+		rp.destroy(obj)
+	}
+}
+
+// Discard destroys an object that was retrieved with Get instead of returning it,
+// e.g. after an I/O error made it unusable.
+func (rp *ResourcePool) Discard(obj interface{}) {
+	if obj == nil {
+		return
+	}
+	atomic.AddInt64(&rp.inUse, -1)
+	rp.pool.Discard(obj)
+	rp.destroy(obj)
+}
+
+// InUse returns the number of objects currently checked out.
+func (rp *ResourcePool) InUse() int {
+	return int(atomic.LoadInt64(&rp.inUse))
+}
+
+// PeakInUse returns the highest number of objects checked out at the same time.
+func (rp *ResourcePool) PeakInUse() int {
+	return int(atomic.LoadInt64(&rp.peakInUse))
+}
+
+// Close stops the background checker and destroys all idle objects.
+// Objects returned after Close are destroyed.
+func (rp *ResourcePool) Close() {
+	if !atomic.CompareAndSwapInt32(&rp.closed, 0, 1) {
+		return
+	}
+	close(rp.stop)
+	rp.wg.Wait()
+	for i := range rp.pool.shards {
+		for _, e := range rp.pool.takeIdle(i) {
+			rp.destroy(e.obj)
+		}
+	}
+}
+
+// checkout counts an object handed out and updates the peak.
+func (rp *ResourcePool) checkout() {
+	n := atomic.AddInt64(&rp.inUse, 1)
+	for {
+		peak := atomic.LoadInt64(&rp.peakInUse)
+		if n <= peak || atomic.CompareAndSwapInt64(&rp.peakInUse, peak, n) {
+			return
+		}
+	}
+}
+
+// checkLoop periodically health checks the idle objects until the pool is closed.
+func (rp *ResourcePool) checkLoop() {
+	defer rp.wg.Done()
+	ticker := time.NewTicker(rp.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rp.stop:
+			return
+		case <-ticker.C:
+			rp.checkIdle()
+		}
+	}
+}
+
+// checkIdle health checks the idle objects one at a time, destroying the
+// unhealthy ones and returning the healthy ones to their shard.
+func (rp *ResourcePool) checkIdle() {
+	cfg := rp.pool.config()
+	for i := range rp.pool.shards {
+		rp.pool.visitIdle(i, func(e entry) {
+			e.hint = shardHint{key: uint64(i), set: true}
+			if !rp.healthCheck(e.obj) || !rp.pool.restore(e, cfg) {
+				rp.destroy(e.obj)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// conn is a test resource.
+type conn struct {
+	healthy int32
+	closed  int32
+}
+
+// newConnPool creates a resource pool of conns that counts destroyed objects.
+func newConnPool(destroyed *int32, opts ...ResourceOption) *ResourcePool {
+	p := predictable(NewPool(func() interface{} {
+		return &conn{healthy: 1}
+	}))
+	opts = append([]ResourceOption{
+		WithHealthCheck(func(obj interface{}) bool {
+			return atomic.LoadInt32(&obj.(*conn).healthy) == 1
+		}),
+		WithDestroy(func(obj interface{}) {
+			atomic.StoreInt32(&obj.(*conn).closed, 1)
+			atomic.AddInt32(destroyed, 1)
+		}),
+	}, opts...)
+	return NewResourcePool(p, opts...)
+}
+
+// TestResourcePool tests health checks on Get and in-use accounting.
+func TestResourcePool(t *testing.T) {
+	var destroyed int32
+	rp := newConnPool(&destroyed)
+	ctx := context.Background()
+
+	a, _ := rp.Get(ctx)
+	b, _ := rp.Get(ctx)
+	if rp.InUse() != 2 || rp.PeakInUse() != 2 {
+		t.Errorf("Expected 2 in use, got %d (peak %d)", rp.InUse(), rp.PeakInUse())
+	}
+
+	a.(*conn).healthy = 0
+	rp.Put(a)
+	rp.Discard(b)
+	if rp.InUse() != 0 || destroyed != 1 {
+		t.Errorf("Expected 0 in use and 1 destroyed, got %d and %d", rp.InUse(), destroyed)
+	}
+
+	obj, err := rp.Get(ctx)
+	if err != nil || obj == a {
+		t.Error("Expected the unhealthy object to be skipped")
+	}
+	if destroyed != 2 || a.(*conn).closed != 1 {
+		t.Error("Expected the unhealthy object to be destroyed")
+	}
+	rp.Put(obj)
+
+	rp.Close()
+	if destroyed != 3 {
+		t.Errorf("Expected Close to destroy idle objects, got %d destroyed", destroyed)
+	}
+	if _, err := rp.Get(ctx); err != ErrClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+// TestResourcePoolBackgroundCheck tests that idle objects are checked periodically.
+func TestResourcePoolBackgroundCheck(t *testing.T) {
+	var destroyed int32
+	rp := newConnPool(&destroyed, WithHealthCheckInterval(5*time.Millisecond))
+	defer rp.Close()
+
+	obj, _ := rp.Get(context.Background())
+	rp.Put(obj)
+	atomic.StoreInt32(&obj.(*conn).healthy, 0)
+
+	for i := 0; atomic.LoadInt32(&destroyed) == 0 && i < 100; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&destroyed) != 1 {
+		t.Error("Expected the background checker to destroy the unhealthy object")
+	}
+}
+
+// TestResourcePoolInUseLimit tests that destroyed objects release their in-use slots.
+func TestResourcePoolInUseLimit(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return &conn{}
+	}, WithMaxInUse(2, false)))
+	rp := NewResourcePool(p, WithHealthCheck(func(obj interface{}) bool {
+		return atomic.LoadInt32(&obj.(*conn).healthy) == 1
+	}))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := rp.Get(ctx); err != ErrUnhealthy {
+			t.Fatalf("Expected ErrUnhealthy, got %v", err)
+		}
+	}
+	if n := p.Semaphore().Available(); n != 2 {
+		t.Errorf("Expected unhealthy objects to free their slots, got %d free", n)
+	}
+
+	putShard(p, 0, &conn{healthy: 1})
+	obj, err := rp.Get(ctx)
+	if err != nil {
+		t.Fatalf("Expected the healthy idle object, got %v", err)
+	}
+	rp.Close()
+	rp.Put(obj)
+	if n := p.Semaphore().Available(); n != 2 {
+		t.Errorf("Expected a Put after Close to free its slot, got %d free", n)
+	}
+}
+
+// TestResourcePoolCheckIdleShards tests that the checker keeps healthy objects in their shards.
+func TestResourcePoolCheckIdleShards(t *testing.T) {
+	var destroyed int32
+	rp := newConnPool(&destroyed)
+	for i := 0; i < shardCount*100; i++ {
+		putShard(rp.pool, uint64(i), &conn{healthy: 1})
+	}
+	rp.checkIdle()
+	if n := idleCount(rp.pool); n != shardCount*100 {
+		t.Errorf("Expected %d healthy idle objects, got %d", shardCount*100, n)
+	}
+	if destroyed != 0 {
+		t.Errorf("Expected no object to be destroyed, got %d", destroyed)
+	}
+}
+
+// TestResourcePoolCheckIdleOneAtATime tests that the shard keeps its other objects while one is checked.
+func TestResourcePoolCheckIdleOneAtATime(t *testing.T) {
+	var rp *ResourcePool
+	checked := 0
+	rp = NewResourcePool(predictable(NewPool(func() interface{} {
+		return &conn{healthy: 1}
+	})), WithHealthCheck(func(obj interface{}) bool {
+		checked++
+		if n := rp.pool.shards[0].idle(); n != 3 {
+			t.Errorf("Expected the other 3 objects to stay idle during a check, got %d", n)
+		}
+		return true
+	}))
+	for i := 0; i < 4; i++ {
+		putShard(rp.pool, 0, &conn{healthy: 1})
+	}
+
+	rp.checkIdle()
+	if checked != 4 {
+		t.Errorf("Expected 4 health checks, got %d", checked)
+	}
+	if n := idleCount(rp.pool); n != 4 {
+		t.Errorf("Expected 4 healthy idle objects, got %d", n)
+	}
+}
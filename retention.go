@@ -0,0 +1,71 @@
+package pool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Interval at which the idle objects converge toward the retention target
+const retentionInterval = time.Second
+
+// startRetention starts converging the number of idle objects toward the
+// retention target of the pool's configuration.
+func (p *Pool) startRetention() {
+	p.runTask(retentionInterval, func(p *Pool) {
+		if cfg := p.config(); cfg.retentionTarget > 0 {
+			p.retain(cfg.retentionTarget, cfg)
+		}
+	})
+}
+
+// retain closes half of the gap between the number of idle objects and
+// target, destroying the least recently returned objects of every shard in
+// proportion to its size or creating new ones, and returns the change of the
+// idle count.
+func (p *Pool) retain(target int, cfg *config) int {
+	idle := p.Len()
+	switch {
+	case idle > target:
+		p.trimN((idle-target+1)/2, idle, cfg)
+	case idle < target:
+		deficit := (target - idle + 1) / 2
+		for i := 0; i < deficit; i++ {
+			if !p.addNew(cfg) {
+				break
+			}
+		}
+	}
+	return p.Len() - idle
+}
+
+// trimN destroys n of the idle objects, of which there are about idle, taking
+// from every shard in proportion to its size.
+func (p *Pool) trimN(n, idle int, cfg *config) {
+	quotas := make([]int, len(p.shards))
+	depths := make([]int, len(p.shards))
+	left := n
+	for i := range p.shards {
+		depths[i] = int(atomic.LoadInt32(&p.shards[i].depth))
+		quotas[i] = n * depths[i] / idle
+		left -= quotas[i]
+	}
+	// Hand out the rounding remainder one object at a time to the shards
+	// keeping the most
+	for ; left > 0; left-- {
+		best := -1
+		for i := range quotas {
+			if depths[i]-quotas[i] > 0 && (best < 0 || depths[i]-quotas[i] > depths[best]-quotas[best]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		quotas[best]++
+	}
+	for i, q := range quotas {
+		if q > 0 {
+			p.trimShard(i, depths[i]-q, cfg)
+		}
+	}
+}
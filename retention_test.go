@@ -0,0 +1,63 @@
+package pool
+
+import "testing"
+
+// TestRetentionTarget tests that the idle objects converge toward the target.
+func TestRetentionTarget(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	cfg := p.config()
+
+	if n := p.retain(8, cfg); n != 4 {
+		t.Errorf("Expected half of the deficit to be created, got %d", n)
+	}
+	for i := 0; i < 10; i++ {
+		p.retain(8, cfg)
+	}
+	if n := idleCount(p); n != 8 {
+		t.Errorf("Expected to converge to 8 idle objects, got %d", n)
+	}
+
+	for i := 0; i < 24; i++ {
+		putShard(p, uint64(i), new(int))
+	}
+	if n := p.retain(8, cfg); n != -12 {
+		t.Errorf("Expected half of the excess to be destroyed, got %d", n)
+	}
+	for i := 0; i < 20; i++ {
+		p.retain(8, cfg)
+	}
+	if n := idleCount(p); n != 8 {
+		t.Errorf("Expected to converge back to 8 idle objects, got %d", n)
+	}
+}
+
+// TestRetentionTargetShards tests targets above the capacity of one shard.
+func TestRetentionTargetShards(t *testing.T) {
+	calls := 0
+	p := predictable(NewPool(func() interface{} {
+		calls++
+		return new(int)
+	}))
+	cfg := p.config()
+	for i := 0; i < 20; i++ {
+		p.retain(1000, cfg)
+	}
+	if n := idleCount(p); n != 1000 {
+		t.Errorf("Expected to converge to 1000 idle objects, got %d", n)
+	}
+
+	full := shardCount * shardCap
+	for i := 0; i < 30; i++ {
+		p.retain(full+100, cfg)
+	}
+	before := calls
+	p.retain(full+100, cfg)
+	if n := idleCount(p); n != full {
+		t.Errorf("Expected the shards to fill up to %d idle objects, got %d", full, n)
+	}
+	if calls != before {
+		t.Errorf("Expected no factory call once the shards are full, got %d", calls-before)
+	}
+}
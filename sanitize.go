@@ -0,0 +1,75 @@
+package pool
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Byte written over the storage of sanitized objects
+const poisonByte = 0xA5
+
+// Poisoner is implemented by objects the sanitizer can poison.
+// Byte slices, *[]byte and *bytes.Buffer are supported without implementing it.
+type Poisoner interface {
+	// Poison overwrites the object's contents with a recognizable pattern.
+	Poison()
+	// Poisoned reports whether the contents still hold the pattern written by Poison.
+	Poisoned() bool
+}
+
+// poison overwrites the storage of obj, if its type is supported.
+func poison(obj interface{}) {
+	switch o := obj.(type) {
+	case []byte:
+		poisonBytes(o[:cap(o)])
+	case *[]byte:
+		poisonBytes((*o)[:cap(*o)])
+	case *bytes.Buffer:
+		// Reset first, so a buffer returned with unread data is not taken for
+		// one written to after Put
+		o.Reset()
+		b := o.Bytes()
+		poisonBytes(b[:cap(b)])
+	case Poisoner:
+		o.Poison()
+	}
+}
+
+// poisoned reports whether the storage of obj still holds the poison pattern.
+// Objects of unsupported types are always reported as poisoned.
+func poisoned(obj interface{}) bool {
+	switch o := obj.(type) {
+	case []byte:
+		return isPoisoned(o[:cap(o)])
+	case *[]byte:
+		return isPoisoned((*o)[:cap(*o)])
+	case *bytes.Buffer:
+		b := o.Bytes()
+		return o.Len() == 0 && isPoisoned(b[:cap(b)])
+	case Poisoner:
+		return o.Poisoned()
+	}
+	return true
+}
+
+// poisonBytes fills b with the poison byte.
+func poisonBytes(b []byte) {
+	for i := range b {
+		b[i] = poisonByte
+	}
+}
+
+// isPoisoned reports whether every byte of b is the poison byte.
+func isPoisoned(b []byte) bool {
+	for _, c := range b {
+		if c != poisonByte {
+			return false
+		}
+	}
+	return true
+}
+
+// panicUseAfterPut is the default sanitizer handler.
+func panicUseAfterPut(obj interface{}) {
+	panic(fmt.Sprintf("pool: object %T was modified after Put", obj))
+}
@@ -0,0 +1,6 @@
+//go:build !pool_sanitize
+
+package pool
+
+// Building with the pool_sanitize tag enables the sanitizer for every pool
+const sanitizeDefault = false
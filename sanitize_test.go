@@ -0,0 +1,87 @@
+package pool
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestSanitizer tests that writes after Put are detected on the next Get.
+func TestSanitizer(t *testing.T) {
+	var reports []interface{}
+	p := predictable(NewPool(func() interface{} {
+		return make([]byte, 8)
+	}, WithSanitizer(func(obj interface{}) {
+		reports = append(reports, obj)
+	})))
+
+	b := p.Get().([]byte)
+	p.Put(b)
+	if !isPoisoned(b) {
+		t.Fatal("Expected the slice to be poisoned on Put")
+	}
+
+	b[0] = 1 // use after Put
+	p.Get()
+	if len(reports) != 1 {
+		t.Errorf("Expected one use-after-Put report, got %d", len(reports))
+	}
+
+	p.Put(b)
+	if got := p.Get().([]byte); !isPoisoned(got) || len(reports) != 1 {
+		t.Error("Expected an untouched object to be handed out poisoned without a report")
+	}
+}
+
+// TestPoison tests poisoning of the supported types.
+func TestPoison(t *testing.T) {
+	bs := make([]byte, 4)
+	buf := bytes.NewBuffer(make([]byte, 0, 4))
+	objs := []interface{}{bs[:2], &bs, buf}
+	for _, obj := range objs {
+		poison(obj)
+		if !poisoned(obj) {
+			t.Errorf("Expected %T to be poisoned", obj)
+		}
+	}
+
+	buf.WriteByte(1)
+	if poisoned(buf) {
+		t.Error("Expected a written buffer not to be poisoned")
+	}
+	if !poisoned(new(int)) {
+		t.Error("Expected unsupported types to be reported as poisoned")
+	}
+}
+
+// TestPoisonUnresetBuffer tests that buffers returned with data are not reported.
+func TestPoisonUnresetBuffer(t *testing.T) {
+	buf := bytes.NewBufferString("left over")
+	poison(buf)
+	if !poisoned(buf) {
+		t.Error("Expected a buffer Put with data to be poisoned")
+	}
+}
+
+// TestSanitizerPrefilled tests that objects added without a Put are handed out without a report.
+func TestSanitizerPrefilled(t *testing.T) {
+	reports := 0
+	p := predictable(NewPool(func() interface{} {
+		return make([]byte, 8)
+	}, WithSanitizer(func(obj interface{}) {
+		reports++
+	})))
+
+	clone := predictable(p.CloneWithObjects(32))
+	n := idleCount(clone)
+	if n == 0 {
+		t.Fatal("Expected the clone to be warmed up")
+	}
+	for i := 0; i < n; i++ {
+		if b := clone.Get().([]byte); !isPoisoned(b) {
+			t.Fatal("Expected the prefilled objects to be poisoned")
+		}
+	}
+	if reports != 0 {
+		t.Errorf("Expected no use-after-Put report, got %d", reports)
+	}
+}
@@ -0,0 +1,159 @@
+package pool
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// semaphore is a weighted semaphore whose size can be changed at runtime.
+// Waiters are served in FIFO order, so large requests are not starved by small ones.
+// The zero value has size zero and must be resized before use.
+type semaphore struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List
+	// Closed by close to wake the waiters, created on demand
+	done chan struct{}
+}
+
+// semWaiter is a goroutine blocked in acquire.
+type semWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// acquire acquires n units, blocking until they are available, ctx is done or
+// the semaphore is closed, in which case it returns ErrPoolClosed.
+func (s *semaphore) acquire(ctx context.Context, n int64) error {
+	s.mu.Lock()
+	done := s.doneLocked()
+	select {
+	case <-done:
+		s.mu.Unlock()
+		return ErrPoolClosed
+	default:
+	}
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	if n > s.size {
+		// The request can never be satisfied, wait for cancellation
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+			return ErrPoolClosed
+		}
+	}
+
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(semWaiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	var err error
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-done:
+		err = ErrPoolClosed
+	}
+	s.mu.Lock()
+	select {
+	case <-ready:
+		// Acquired just after giving up, give the units back
+		s.cur -= n
+		s.notify()
+	default:
+		front := s.waiters.Front() == elem
+		s.waiters.Remove(elem)
+		if front && s.size > s.cur {
+			s.notify()
+		}
+	}
+	s.mu.Unlock()
+	return err
+}
+
+// close wakes the goroutines blocked in acquire and makes later calls fail
+// with ErrPoolClosed.
+func (s *semaphore) close() {
+	s.mu.Lock()
+	done := s.doneLocked()
+	select {
+	case <-done:
+	default:
+		close(done)
+	}
+	s.mu.Unlock()
+}
+
+// doneLocked returns the channel closed by close.
+// s.mu must be held.
+func (s *semaphore) doneLocked() chan struct{} {
+	if s.done == nil {
+		s.done = make(chan struct{})
+	}
+	return s.done
+}
+
+// tryAcquire acquires n units without blocking and reports whether it succeeded.
+func (s *semaphore) tryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// release releases n units. Releasing more units than are held releases all of them.
+func (s *semaphore) release(n int64) {
+	s.mu.Lock()
+	s.cur -= n
+	if s.cur < 0 {
+		s.cur = 0
+	}
+	s.notify()
+	s.mu.Unlock()
+}
+
+// resize changes the size of the semaphore.
+func (s *semaphore) resize(size int64) {
+	s.mu.Lock()
+	s.size = size
+	s.notify()
+	s.mu.Unlock()
+}
+
+// held returns the number of units currently acquired.
+func (s *semaphore) held() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur
+}
+
+// notify wakes the waiters at the front of the queue that fit into the free units.
+// s.mu must be held.
+func (s *semaphore) notify() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+		w := next.Value.(semWaiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
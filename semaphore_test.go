@@ -0,0 +1,60 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestSemaphore tests acquiring, blocking and releasing units.
+func TestSemaphore(t *testing.T) {
+	var s semaphore
+	s.resize(2)
+	ctx := context.Background()
+
+	if err := s.acquire(ctx, 2); err != nil {
+		t.Fatalf("Expected acquire to succeed, got %v", err)
+	}
+	if s.tryAcquire(1) {
+		t.Error("Expected tryAcquire to fail on a full semaphore")
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- s.acquire(ctx, 1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	s.release(1)
+	if err := <-done; err != nil {
+		t.Errorf("Expected the waiter to acquire after release, got %v", err)
+	}
+	if n := s.held(); n != 2 {
+		t.Errorf("Expected 2 units held, got %d", n)
+	}
+
+	s.release(5)
+	if n := s.held(); n != 0 {
+		t.Errorf("Expected over-release to clamp at 0, got %d", n)
+	}
+}
+
+// TestSemaphoreCancel tests that a canceled waiter gives up and does not hold units.
+func TestSemaphoreCancel(t *testing.T) {
+	var s semaphore
+	s.resize(1)
+	s.tryAcquire(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.acquire(ctx, 1); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if err := s.acquire(ctx, 2); err != context.DeadlineExceeded {
+		t.Errorf("Expected an oversized request to wait for cancellation, got %v", err)
+	}
+
+	s.release(1)
+	if !s.tryAcquire(1) {
+		t.Error("Expected the canceled waiter not to hold units")
+	}
+}
@@ -0,0 +1,98 @@
+package pool
+
+import "math/bits"
+
+const (
+	// Default capacity of the smallest slice class
+	defaultSliceMinCap = 16
+	// Default capacity of the largest slice class
+	defaultSliceMaxCap = 1 << 20
+)
+
+// SlicePool is a pool of []T split into power-of-two capacity classes.
+// Slices are returned with zero length; their elements are cleared on Put
+// so pooled slices never keep referenced values alive.
+type SlicePool[T any] struct {
+	minShift int
+	classes  []*Pool
+}
+
+// NewSlicePool creates a new slice pool with capacity classes from 16 to 1<<20 elements.
+func NewSlicePool[T any]() *SlicePool[T] {
+	return NewSlicePoolSize[T](defaultSliceMinCap, defaultSliceMaxCap)
+}
+
+// NewSlicePoolSize creates a new slice pool with capacity classes from minCap to maxCap.
+// Both bounds are rounded up to a power of two.
+func NewSlicePoolSize[T any](minCap, maxCap int) *SlicePool[T] {
+	if minCap <= 0 || maxCap < minCap {
+		panic("invalid slice pool capacity range")
+	}
+	minShift := ceilShift(minCap)
+	maxShift := ceilShift(maxCap)
+	sp := &SlicePool[T]{
+		minShift: minShift,
+		classes:  make([]*Pool, maxShift-minShift+1),
+	}
+	for i := range sp.classes {
+		size := 1 << (minShift + i)
+		sp.classes[i] = NewPool(func() interface{} {
+			return make([]T, 0, size)
+		})
+	}
+	return sp
+}
+
+// Get retrieves a zero-length slice with a capacity of at least minCap.
+// Requests larger than the biggest class are allocated directly.
+func (sp *SlicePool[T]) Get(minCap int) []T {
+	idx := getClass(minCap, sp.minShift)
+	if idx >= len(sp.classes) {
+		return make([]T, 0, minCap)
+	}
+	return sp.classes[idx].Get().([]T)
+}
+
+// Put returns a slice to the class matching its capacity.
+// Slices outside the range of classes are dropped.
+func (sp *SlicePool[T]) Put(s []T) {
+	idx := putClass(cap(s), sp.minShift)
+	if idx < 0 || idx >= len(sp.classes) {
+		return
+	}
+	s = s[:cap(s)]
+	clear(s)
+	sp.classes[idx].Put(s[:0])
+}
+
+// Clear clears all slices from the pool.
+func (sp *SlicePool[T]) Clear() {
+	for _, p := range sp.classes {
+		p.Clear()
+	}
+}
+
+// ceilShift returns the smallest shift such that 1<<shift >= n.
+func ceilShift(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// getClass returns the smallest class, starting at 1<<minShift, that can hold size elements.
+func getClass(size, minShift int) int {
+	if size <= 1<<minShift {
+		return 0
+	}
+	return ceilShift(size) - minShift
+}
+
+// putClass returns the largest class, starting at 1<<minShift, whose size does not
+// exceed capacity, or -1 if capacity is below the smallest class.
+func putClass(capacity, minShift int) int {
+	if capacity < 1<<minShift {
+		return -1
+	}
+	return bits.Len(uint(capacity)) - 1 - minShift
+}
@@ -0,0 +1,50 @@
+package pool
+
+import "testing"
+
+// TestSlicePoolGet tests that Get returns zero-length slices of sufficient capacity.
+func TestSlicePoolGet(t *testing.T) {
+	sp := NewSlicePool[int]()
+
+	for _, n := range []int{0, 1, 16, 17, 1000, defaultSliceMaxCap + 1} {
+		s := sp.Get(n)
+		if len(s) != 0 {
+			t.Errorf("Expected zero-length slice for %d, got len %d", n, len(s))
+		}
+		if cap(s) < n {
+			t.Errorf("Expected capacity >= %d, got %d", n, cap(s))
+		}
+		sp.Put(append(s, 1, 2, 3))
+	}
+}
+
+// TestSlicePoolClear tests that Put clears the elements of returned slices.
+func TestSlicePoolClear(t *testing.T) {
+	sp := NewSlicePoolSize[*int](4, 64)
+	for _, p := range sp.classes {
+		predictable(p)
+	}
+
+	s := sp.Get(4)
+	s = append(s, new(int), new(int))
+	sp.Put(s)
+
+	for _, v := range s[:2] {
+		if v != nil {
+			t.Error("Expected elements to be cleared on Put")
+		}
+	}
+	if n := idleCount(sp.classes[0]); n != 1 {
+		t.Errorf("Expected 1 slice in the smallest class, got %d", n)
+	}
+}
+
+// TestSlicePoolSizePanic tests that an invalid capacity range panics.
+func TestSlicePoolSizePanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for invalid capacity range")
+		}
+	}()
+	NewSlicePoolSize[int](64, 4)
+}
@@ -0,0 +1,55 @@
+package pool
+
+import (
+	"context"
+	"time"
+)
+
+// SlowOp identifies an operation of a Get reported by WithSlowHandler.
+type SlowOp int
+
+const (
+	// SlowWait is a wait for an in-use slot, see WithMaxInUse
+	SlowWait SlowOp = iota
+	// SlowFactory is a factory call creating a new object
+	SlowFactory
+)
+
+// String returns the name of op.
+func (op SlowOp) String() string {
+	switch op {
+	case SlowWait:
+		return "wait"
+	case SlowFactory:
+		return "factory"
+	default:
+		return "unknown"
+	}
+}
+
+// slowHandler reports the operations of Gets that take longer than their threshold.
+type slowHandler struct {
+	// Threshold of waits for an in-use slot, zero to not report them
+	wait time.Duration
+	// Threshold of factory calls, zero to not report them
+	factory time.Duration
+	fn      func(ctx context.Context, op SlowOp, d time.Duration)
+}
+
+// threshold returns the threshold of op, zero if it is not reported.
+func (h *slowHandler) threshold(op SlowOp) time.Duration {
+	if h == nil {
+		return 0
+	}
+	if op == SlowWait {
+		return h.wait
+	}
+	return h.factory
+}
+
+// observe reports op if it took d and that exceeds its threshold.
+func (h *slowHandler) observe(ctx context.Context, op SlowOp, d time.Duration) {
+	if t := h.threshold(op); t > 0 && d > t {
+		h.fn(ctx, op, d)
+	}
+}
@@ -0,0 +1,28 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestSlowHandler tests that only operations above their threshold are reported.
+func TestSlowHandler(t *testing.T) {
+	var ops []SlowOp
+	p := NewPool(func() interface{} {
+		time.Sleep(5 * time.Millisecond)
+		return new(int)
+	}, WithMaxInUse(1, true), WithSlowHandler(time.Millisecond, time.Hour, func(ctx context.Context, op SlowOp, d time.Duration) {
+		ops = append(ops, op)
+	}))
+
+	obj := p.Get()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	p.GetContext(ctx)
+	p.Put(obj)
+
+	if len(ops) != 1 || ops[0] != SlowWait {
+		t.Errorf("Expected only the slow wait to be reported, got %v", ops)
+	}
+}
@@ -0,0 +1,101 @@
+package pool
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Max size of a record read by Restore (64MB)
+const maxSnapshotRecord = 64 << 20
+
+// Snapshot writes the idle objects to w, each encoded with enc, so a later
+// process can warm its pool with Restore instead of regenerating them.
+// Objects are encoded under their shard's lock and stay in the pool; enc must
+// not call back into the pool. The encoding is a sequence of uvarint-prefixed
+// records.
+func (p *Pool) Snapshot(w io.Writer, enc func(obj interface{}) ([]byte, error)) error {
+	bw := bufio.NewWriter(w)
+	var err error
+	var prefix [binary.MaxVarintLen64]byte
+	p.Range(func(obj interface{}) bool {
+		var b []byte
+		if b, err = enc(obj); err != nil {
+			return false
+		}
+		n := binary.PutUvarint(prefix[:], uint64(len(b)))
+		if _, err = bw.Write(prefix[:n]); err != nil {
+			return false
+		}
+		_, err = bw.Write(b)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Restore reads objects written by Snapshot from r, decodes each with dec and
+// adds them to the pool as idle objects. It returns the number of objects
+// retained, which is lower than the number read once the pool is full.
+// The objects are spread over the shards and, if the pool sanitizes, poisoned
+// like returned ones. Records larger than 64MB fail.
+func (p *Pool) Restore(r io.Reader, dec func(b []byte) (interface{}, error)) (int, error) {
+	br := bufio.NewReader(r)
+	cfg := p.config()
+	n := 0
+	var read uint64
+	for {
+		size, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if size > maxSnapshotRecord {
+			return n, fmt.Errorf("pool: snapshot record of %d bytes exceeds the max of %d", size, maxSnapshotRecord)
+		}
+		// Grow with the data actually read, so a corrupt size cannot allocate
+		// more than the input holds
+		var buf bytes.Buffer
+		if m, err := io.CopyN(&buf, br, int64(size)); err != nil {
+			if err == io.EOF && m < int64(size) {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+		obj, err := dec(buf.Bytes())
+		if err != nil {
+			return n, err
+		}
+		if obj == nil {
+			return n, errors.New("pool: Restore decoded a nil object")
+		}
+		// Spread the objects over the shards instead of the caller's shard
+		e := p.newEntry(obj, cfg)
+		e.hint = shardHint{key: read, set: true}
+		read++
+		if p.restore(e, cfg) {
+			n++
+		}
+	}
+}
+
+// newEntry returns an idle entry for obj, which was not created by the factory.
+func (p *Pool) newEntry(obj interface{}, cfg *config) entry {
+	e := entry{obj: obj, epoch: atomic.LoadUint64(&p.epoch)}
+	if cfg.metadata {
+		now := cfg.clock.Now()
+		e.meta = &objectMeta{created: now, lastUsed: now, epoch: e.epoch}
+	}
+	if cfg.sizer != nil {
+		e.size = cfg.sizer(obj)
+	}
+	return e
+}
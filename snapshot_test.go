@@ -0,0 +1,132 @@
+package pool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+)
+
+// TestSnapshotRestore tests that a snapshot restores the idle objects into another pool.
+func TestSnapshotRestore(t *testing.T) {
+	newInt := func() interface{} {
+		return new(int)
+	}
+	p := predictable(NewPool(newInt))
+	for i := 1; i <= 3; i++ {
+		v := i
+		p.Put(&v)
+	}
+
+	var buf bytes.Buffer
+	err := p.Snapshot(&buf, func(obj interface{}) ([]byte, error) {
+		return []byte(strconv.Itoa(*obj.(*int))), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := idleCount(p); n != 3 {
+		t.Errorf("Expected the objects to stay in the pool, got %d", n)
+	}
+
+	q := predictable(NewPool(newInt))
+	n, err := q.Restore(&buf, func(b []byte) (interface{}, error) {
+		v, err := strconv.Atoi(string(b))
+		return &v, err
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("Expected 3 restored objects, got %d and %v", n, err)
+	}
+	sum := 0
+	q.Range(func(obj interface{}) bool {
+		sum += *obj.(*int)
+		return true
+	})
+	if sum != 6 {
+		t.Errorf("Expected the restored values to add up to 6, got %d", sum)
+	}
+}
+
+// TestSnapshotErrors tests that encoding and decoding errors are returned.
+func TestSnapshotErrors(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	p.Put(new(int))
+
+	errEnc := errors.New("enc")
+	if err := p.Snapshot(&bytes.Buffer{}, func(obj interface{}) ([]byte, error) {
+		return nil, errEnc
+	}); err != errEnc {
+		t.Errorf("Expected the encoding error, got %v", err)
+	}
+
+	truncated := bytes.NewReader([]byte{5, 'a'})
+	if _, err := p.Restore(truncated, func(b []byte) (interface{}, error) {
+		return b, nil
+	}); err == nil {
+		t.Error("Expected an error for a truncated snapshot")
+	}
+}
+
+// TestRestoreCorrupt tests that a hostile record size fails instead of panicking.
+func TestRestoreCorrupt(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	dec := func(b []byte) (interface{}, error) { return b, nil }
+	huge := binary.AppendUvarint(nil, 1<<63)
+	if _, err := p.Restore(bytes.NewReader(huge), dec); err == nil {
+		t.Error("Expected an error for a record above the max size")
+	}
+	lying := binary.AppendUvarint(nil, maxSnapshotRecord)
+	if _, err := p.Restore(bytes.NewReader(append(lying, 'a')), dec); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF for a record longer than the input, got %v", err)
+	}
+}
+
+// TestRestoreShards tests that restored objects are spread over the shards.
+func TestRestoreShards(t *testing.T) {
+	var snap []byte
+	for i := 0; i < 1000; i++ {
+		snap = append(binary.AppendUvarint(snap, 1), byte(i))
+	}
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	n, err := p.Restore(bytes.NewReader(snap), func(b []byte) (interface{}, error) {
+		v := int(b[0])
+		return &v, nil
+	})
+	if err != nil || n != 1000 {
+		t.Errorf("Expected 1000 restored objects, got %d and %v", n, err)
+	}
+}
+
+// TestRestoreSanitized tests that restored objects are handed out by a sanitized pool without a report.
+func TestRestoreSanitized(t *testing.T) {
+	var snap []byte
+	for i := 0; i < 3; i++ {
+		snap = append(binary.AppendUvarint(snap, 4), "data"...)
+	}
+	reports := 0
+	p := predictable(NewPool(func() interface{} {
+		return make([]byte, 4)
+	}, WithSanitizer(func(obj interface{}) {
+		reports++
+	})))
+	n, err := p.Restore(bytes.NewReader(snap), func(b []byte) (interface{}, error) {
+		return bytes.Clone(b), nil
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("Expected 3 restored objects, got %d and %v", n, err)
+	}
+	for i := 0; i < n; i++ {
+		p.Get()
+	}
+	if reports != 0 {
+		t.Errorf("Expected no use-after-Put report, got %d", reports)
+	}
+}
@@ -0,0 +1,64 @@
+package pool
+
+import "time"
+
+// pushSoftLocked adds an entry that did not fit the shard to its soft tier,
+// to decay after the soft TTL of cfg, and reports whether there was room.
+// s.mu must be held.
+func (s *poolShard) pushSoftLocked(e entry, cfg *config) bool {
+	if len(s.soft) >= cfg.softCap {
+		return false
+	}
+	e.expires = cfg.clock.Now().Add(softTTL(cfg)).UnixNano()
+	s.soft = append(s.soft, e)
+	return true
+}
+
+// softTTL returns the time objects stay in the soft tier under cfg.
+func softTTL(cfg *config) time.Duration {
+	if cfg.softTTL <= 0 {
+		return time.Second
+	}
+	return cfg.softTTL
+}
+
+// startSoftCap starts destroying soft objects once their TTL passed.
+func (p *Pool) startSoftCap(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+	p.runTask(max(ttl/2, time.Millisecond), func(p *Pool) {
+		p.decay(p.config().clock.Now())
+	})
+}
+
+// decay destroys the soft objects that expired before now and returns their
+// number. Soft entries are ordered by age, so each shard decays a prefix of
+// its tier.
+func (p *Pool) decay(now time.Time) int {
+	cfg := p.config()
+	n := 0
+	for i := range p.shards {
+		shard := &p.shards[i]
+		shard.mu.Lock()
+		k := 0
+		for k < len(shard.soft) && shard.soft[k].expires <= now.UnixNano() {
+			k++
+		}
+		if k == 0 {
+			shard.unlock()
+			continue
+		}
+		expired := make([]entry, k)
+		copy(expired, shard.soft[:k])
+		rest := copy(shard.soft, shard.soft[k:])
+		clear(shard.soft[rest:])
+		shard.soft = shard.soft[:rest]
+		shard.unlock()
+		for _, e := range expired {
+			p.evicted(e, cfg)
+		}
+		n += k
+	}
+	return n
+}
@@ -0,0 +1,59 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSoftCap tests that objects above the capacity are kept until they decay.
+func TestSoftCap(t *testing.T) {
+	destroyed := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithShardCap(1), WithSoftCap(2, time.Hour), WithDestructor(func(obj interface{}) {
+		destroyed++
+	})))
+	for i := 0; i < 4; i++ {
+		putShard(p, 0, new(int))
+	}
+
+	shard := &p.shards[0]
+	shard.mu.Lock()
+	cold, soft := len(shard.objs), len(shard.soft)
+	shard.mu.Unlock()
+	if cold != 1 || soft != 2 {
+		t.Errorf("Expected 1 cold and 2 soft objects, got %d and %d", cold, soft)
+	}
+	if n := p.decay(time.Now()); n != 0 {
+		t.Errorf("Expected no soft object to decay yet, got %d", n)
+	}
+	if n := p.decay(time.Now().Add(2 * time.Hour)); n != 2 || destroyed != 2 {
+		t.Errorf("Expected both soft objects to decay, got %d and %d destroyed", n, destroyed)
+	}
+	if n := idleCount(p); n != 1 {
+		t.Errorf("Expected the object within capacity to stay, got %d idle", n)
+	}
+}
+
+// TestSoftCapWholeShard tests that whole-shard operations keep the tiers within their capacities.
+func TestSoftCapWholeShard(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithShardCap(2), WithSoftCap(4, time.Hour)))
+	for i := 0; i < 6; i++ {
+		putShard(p, 0, new(int))
+	}
+
+	p.ClearWhere(func(obj interface{}) bool { return false })
+	p.Trim(10)
+	shard := &p.shards[0]
+	shard.mu.Lock()
+	cold, soft := len(shard.objs), len(shard.soft)
+	shard.mu.Unlock()
+	if cold != 2 || soft != 4 {
+		t.Errorf("Expected 2 cold and 4 soft objects, got %d and %d", cold, soft)
+	}
+	if n := p.decay(time.Now().Add(2 * time.Hour)); n != 4 {
+		t.Errorf("Expected the soft objects to still decay, got %d", n)
+	}
+}
@@ -0,0 +1,180 @@
+package pool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the counters of a pool.
+// It marshals to JSON with stable snake_case field names; durations are in nanoseconds.
+type Stats struct {
+	// Gets served by the pool, including the ones that created a new object
+	Gets uint64 `json:"gets"`
+	// Objects returned with Put
+	Puts uint64 `json:"puts"`
+	// Gets served by an idle object, from the preferred shard or stolen
+	Hits uint64 `json:"hits"`
+	// Gets that had to create a new object
+	Misses uint64 `json:"misses"`
+	// Returned objects dropped because their shard was full
+	Drops uint64 `json:"drops"`
+	// Gets served by an idle object stolen from another shard
+	Steals uint64 `json:"steals"`
+	// Idle objects held by the pool
+	Idle int `json:"idle"`
+	// Objects checked out and not yet returned or discarded
+	InUse int64 `json:"in_use"`
+	// Total time Gets spent waiting for an in-use slot, see WithMaxInUse
+	WaitTime time.Duration `json:"wait_time_ns"`
+	// Distribution of the time Gets waited for an in-use slot, recorded
+	// for every Get of a pool that waits, see WithMaxInUse
+	Waits Histogram `json:"waits"`
+	// Per-shard counters, indexed by shard ID
+	Shards []ShardStats `json:"shards"`
+}
+
+// ShardStats is a snapshot of the counters of a single shard.
+type ShardStats struct {
+	// Idle objects held by the shard
+	Idle int `json:"idle"`
+	// Gets that preferred the shard
+	Gets uint64 `json:"gets"`
+	// Gets that preferred the shard and found an idle object in it
+	Hits uint64 `json:"hits"`
+	// Objects stolen from the shard by Gets that preferred another one
+	Steals uint64 `json:"steals"`
+	// Objects returned to the shard
+	Puts uint64 `json:"puts"`
+	// Objects returned to the shard and dropped because it was full
+	Drops uint64 `json:"drops"`
+	// Capacity of the shard, see WithAutoTune
+	Capacity int `json:"capacity"`
+}
+
+// HitRate returns the fraction of the Gets preferring the shard that it
+// served itself, or 0 if there were none.
+func (s ShardStats) HitRate() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Gets)
+}
+
+// HitRate returns the fraction of Gets served by an idle object, or 0 if
+// there were none.
+func (s Stats) HitRate() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Gets)
+}
+
+// Imbalance returns how unevenly the traffic (Gets and Puts) is spread over
+// the shards: the busiest shard's traffic divided by the mean. It is 1 for a
+// perfectly even spread and len(Shards) when a single shard takes everything;
+// it is 0 if there was no traffic.
+func (s Stats) Imbalance() float64 {
+	var total, busiest uint64
+	for _, sh := range s.Shards {
+		traffic := sh.Gets + sh.Puts
+		total += traffic
+		if traffic > busiest {
+			busiest = traffic
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(busiest) * float64(len(s.Shards)) / float64(total)
+}
+
+// Stats returns a snapshot of the pool's counters.
+// The counters are read one by one, so they may be slightly inconsistent
+// with each other while the pool is in use.
+func (p *Pool) Stats() Stats {
+	st := Stats{
+		Gets:   atomic.LoadUint64(&p.gets),
+		Puts:   atomic.LoadUint64(&p.puts),
+		Misses: atomic.LoadUint64(&p.misses),
+		Drops:  atomic.LoadUint64(&p.drops),
+		InUse:  p.checkedOut(),
+		Waits:  p.waits.snapshot(),
+		Shards: make([]ShardStats, len(p.shards)),
+	}
+	st.WaitTime = st.Waits.Sum
+	cfg := p.config()
+	for i := range p.shards {
+		shard := &p.shards[i]
+		shard.mu.Lock()
+		idle := shard.idle()
+		shard.unlock()
+		sh := ShardStats{
+			Idle:     idle,
+			Gets:     atomic.LoadUint64(&shard.gets),
+			Hits:     atomic.LoadUint64(&shard.hits),
+			Steals:   atomic.LoadUint64(&shard.steals),
+			Puts:     atomic.LoadUint64(&shard.puts),
+			Drops:    atomic.LoadUint64(&shard.drops),
+			Capacity: shard.limit(cfg),
+		}
+		st.Idle += sh.Idle
+		st.Hits += sh.Hits + sh.Steals
+		st.Steals += sh.Steals
+		st.Shards[i] = sh
+	}
+	return st
+}
+
+// Delta returns the change of the counters since prev, an earlier snapshot of
+// the same pool. Idle and in-use counts are kept as they are in s.
+func (s Stats) Delta(prev Stats) Stats {
+	d := s
+	d.Gets -= prev.Gets
+	d.Puts -= prev.Puts
+	d.Hits -= prev.Hits
+	d.Misses -= prev.Misses
+	d.Drops -= prev.Drops
+	d.Steals -= prev.Steals
+	d.WaitTime -= prev.WaitTime
+	d.Waits = s.Waits.delta(prev.Waits)
+	d.Shards = make([]ShardStats, len(s.Shards))
+	for i, sh := range s.Shards {
+		if i < len(prev.Shards) {
+			old := prev.Shards[i]
+			sh.Gets -= old.Gets
+			sh.Hits -= old.Hits
+			sh.Steals -= old.Steals
+			sh.Puts -= old.Puts
+			sh.Drops -= old.Drops
+		}
+		d.Shards[i] = sh
+	}
+	return d
+}
+
+// StatsDelta returns the current stats and their change since prev, so a
+// periodic reporter can keep the first for its next call and report the second.
+func (p *Pool) StatsDelta(prev Stats) (cur, delta Stats) {
+	cur = p.Stats()
+	return cur, cur.Delta(prev)
+}
+
+// InUse returns the number of objects currently checked out by Gets. Puts of
+// objects the pool did not hand out, e.g. to pre-warm it, are not counted
+// against it while nothing is checked out; they cannot be told apart from
+// returned objects otherwise.
+func (p *Pool) InUse() int {
+	return int(p.checkedOut())
+}
+
+// Len returns the approximate number of idle objects, without taking any lock,
+// for callers that poll it frequently such as dashboards or admission control.
+// It reads the shard depths published when the shards were last unlocked, so
+// it may lag concurrent Gets and Puts; use Stats for an exact count.
+func (p *Pool) Len() int {
+	n := 0
+	for i := range p.shards {
+		n += int(atomic.LoadInt32(&p.shards[i].depth))
+	}
+	return n
+}
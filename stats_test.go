@@ -0,0 +1,177 @@
+package pool
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestStats tests the counters reported by Stats.
+func TestStats(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	obj := p.Get()
+	p.Put(obj)
+	p.Get()
+
+	st := p.Stats()
+	if st.Gets != 2 || st.Puts != 1 || st.Hits != 1 || st.Misses != 1 {
+		t.Errorf("Expected 2 gets, 1 put, 1 hit and 1 miss, got %+v", st)
+	}
+	if st.Idle != 0 || st.InUse != 1 {
+		t.Errorf("Expected 0 idle and 1 in use, got %d and %d", st.Idle, st.InUse)
+	}
+	if len(st.Shards) != shardCount {
+		t.Fatalf("Expected %d shard stats, got %d", shardCount, len(st.Shards))
+	}
+	var gets, puts uint64
+	for _, sh := range st.Shards {
+		gets += sh.Gets
+		puts += sh.Puts
+	}
+	if gets != 2 || puts != 1 {
+		t.Errorf("Expected shard gets and puts to add up to 2 and 1, got %d and %d", gets, puts)
+	}
+	if r := st.HitRate(); r != 0.5 {
+		t.Errorf("Expected hit rate 0.5, got %v", r)
+	}
+}
+
+// TestStatsSteals tests that objects taken from another shard are counted as steals.
+func TestStatsSteals(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	p.Get()
+	other := (busiestShard(p.Stats()) + 1) & p.shardMask
+	p.shards[other].push(entry{obj: new(int)}, shardCap, nil)
+	p.Get()
+
+	st := p.Stats()
+	if st.Steals != 1 || st.Shards[other].Steals != 1 {
+		t.Errorf("Expected 1 steal from shard %d, got %d and %d", other, st.Steals, st.Shards[other].Steals)
+	}
+	if st.Hits != 1 || st.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %d and %d", st.Hits, st.Misses)
+	}
+}
+
+// busiestShard returns the shard preferred by the most Gets in st. Gets from
+// the same function run at the same stack depth and so prefer the same shard.
+func busiestShard(st Stats) uint64 {
+	busiest := 0
+	for i, sh := range st.Shards {
+		if sh.Gets > st.Shards[busiest].Gets {
+			busiest = i
+		}
+	}
+	return uint64(busiest)
+}
+
+// TestImbalance tests the imbalance score.
+func TestImbalance(t *testing.T) {
+	st := Stats{Shards: make([]ShardStats, 4)}
+	if v := st.Imbalance(); v != 0 {
+		t.Errorf("Expected 0 without traffic, got %v", v)
+	}
+	for i := range st.Shards {
+		st.Shards[i].Gets = 10
+	}
+	if v := st.Imbalance(); v != 1 {
+		t.Errorf("Expected 1 for an even spread, got %v", v)
+	}
+	st.Shards = []ShardStats{{Gets: 10}, {}, {}, {}}
+	if v := st.Imbalance(); v != 4 {
+		t.Errorf("Expected 4 for a single busy shard, got %v", v)
+	}
+	if r := (ShardStats{Gets: 4, Hits: 3}).HitRate(); r != 0.75 {
+		t.Errorf("Expected shard hit rate 0.75, got %v", r)
+	}
+}
+
+// TestStatsWaitTime tests that the time Gets wait for an in-use slot is recorded.
+func TestStatsWaitTime(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	}, WithMaxInUse(1, true))
+	obj := p.Get()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.Put(obj)
+	}()
+	p.Get()
+
+	if w := p.Stats().WaitTime; w < 5*time.Millisecond {
+		t.Errorf("Expected the wait to be recorded, got %v", w)
+	}
+}
+
+// TestStatsJSON tests that Stats marshals with stable field names.
+func TestStatsJSON(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+	p.Get()
+
+	b, err := json.Marshal(p.Stats())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, key := range []string{"gets", "puts", "hits", "misses", "steals", "idle", "in_use", "wait_time_ns", "waits", "shards"} {
+		if _, ok := m[key]; !ok {
+			t.Errorf("Expected field %q in %s", key, b)
+		}
+	}
+	if m["gets"] != float64(1) {
+		t.Errorf("Expected 1 get, got %v", m["gets"])
+	}
+}
+
+// TestStatsDelta tests that StatsDelta reports the change of the counters.
+func TestStatsDelta(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	p.Put(p.Get())
+	prev := p.Stats()
+	p.Get()
+	p.Get()
+
+	cur, d := p.StatsDelta(prev)
+	if cur.Gets != 3 || d.Gets != 2 || d.Puts != 0 || d.Hits != 1 || d.Misses != 1 {
+		t.Errorf("Expected 2 gets, 1 hit and 1 miss since prev, got %+v", d)
+	}
+	if d.InUse != cur.InUse || d.Idle != cur.Idle {
+		t.Errorf("Expected gauges to be kept, got %d and %d", d.InUse, d.Idle)
+	}
+	var gets uint64
+	for _, sh := range d.Shards {
+		gets += sh.Gets
+	}
+	if gets != 2 {
+		t.Errorf("Expected shard gets to add up to 2, got %d", gets)
+	}
+}
+
+// TestLen tests that Len follows the idle objects without locking.
+func TestLen(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	for i := 0; i < 3; i++ {
+		putShard(p, uint64(i), new(int))
+	}
+
+	if n := p.Len(); n != 3 {
+		t.Errorf("Expected 3 idle objects, got %d", n)
+	}
+	p.Get()
+	if n := p.Len(); n != 2 {
+		t.Errorf("Expected 2 idle objects after a Get, got %d", n)
+	}
+}
@@ -0,0 +1,40 @@
+package pool
+
+import "sync/atomic"
+
+// stealProbeInterval is how often a Get probes one shard while the adaptive
+// steal budget is zero.
+const stealProbeInterval = 16
+
+// stealBudget is the adaptive number of shards to steal from, see WithAdaptiveSteal.
+type stealBudget struct {
+	n      int32
+	probes uint32
+}
+
+// stealBudget returns the number of shards a Get may steal from under cfg.
+func (p *Pool) stealBudget(cfg *config) int {
+	if !cfg.adaptiveSteal {
+		return cfg.stealShardCnt
+	}
+	if n := int(atomic.LoadInt32(&p.steal.n)); n > 0 {
+		return n
+	}
+	if atomic.AddUint32(&p.steal.probes, 1)%stealProbeInterval == 0 {
+		return 1
+	}
+	return 0
+}
+
+// stealDone adjusts the adaptive steal budget after a search that found an
+// object or not. Concurrent adjustments may overwrite each other, which only
+// delays the adaptation.
+func (p *Pool) stealDone(found bool) {
+	n := atomic.LoadInt32(&p.steal.n)
+	switch {
+	case found && int(n) < len(p.shards)-1:
+		atomic.StoreInt32(&p.steal.n, n+1)
+	case !found && n > 0:
+		atomic.StoreInt32(&p.steal.n, n-1)
+	}
+}
@@ -0,0 +1,98 @@
+package pool
+
+import "testing"
+
+// TestAdaptiveStealShrink tests that failed searches shrink the budget to zero.
+func TestAdaptiveStealShrink(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithAdaptiveSteal()))
+	cfg := p.config()
+	if n := p.stealBudget(cfg); n != stealShardCnt {
+		t.Fatalf("Expected the initial budget %d, got %d", stealShardCnt, n)
+	}
+	for i := 0; i < stealShardCnt; i++ {
+		p.Get()
+	}
+	probes := 0
+	for i := 0; i < stealProbeInterval; i++ {
+		probes += p.stealBudget(cfg)
+	}
+	if probes != 1 {
+		t.Errorf("Expected a single probe per %d Gets once the budget is zero, got %d", stealProbeInterval, probes)
+	}
+}
+
+// TestAdaptiveStealGrow tests that successful steals grow the budget.
+func TestAdaptiveStealGrow(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithAdaptiveSteal()))
+	cfg := p.config()
+	p.Get()
+	for i := 0; i < 2*len(p.shards); i++ {
+		p.stealDone(true)
+	}
+	if n := p.stealBudget(cfg); n != len(p.shards)-1 {
+		t.Errorf("Expected the budget to stop at %d, got %d", len(p.shards)-1, n)
+	}
+
+	far := (busiestShard(p.Stats()) + uint64(len(p.shards)-1)) & p.shardMask
+	p.shards[far].push(entry{obj: new(int)}, shardCap, nil)
+	p.Get()
+	if st := p.Stats(); st.Steals != 1 {
+		t.Errorf("Expected the object on the farthest shard to be stolen, got %d steals", st.Steals)
+	}
+}
+
+// TestStealSkipsEmptyShards tests that empty shards are skipped without their locks.
+func TestStealSkipsEmptyShards(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	obj := new(int)
+	p.shards[3].push(entry{obj: obj}, shardCap, nil)
+	if p.shards[3].empty() || !p.shards[4].empty() {
+		t.Fatal("Expected the depth to follow the idle entries")
+	}
+
+	p.shards[2].mu.Lock()
+	p.shards[2].depth = 0
+	got, ok := p.TryGet()
+	p.shards[2].mu.Unlock()
+	if !ok || got != obj {
+		t.Error("Expected the steal loop to find the object past a locked empty shard")
+	}
+	if !p.shards[3].empty() {
+		t.Error("Expected the shard to be empty after the pop")
+	}
+}
+
+// TestStealCandidates tests the window of the bitmap of non-empty shards.
+func TestStealCandidates(t *testing.T) {
+	p := NewPool(func() interface{} {
+		return new(int)
+	})
+	p.shards[1].push(entry{obj: new(int)}, shardCap, nil)
+	p.shards[14].push(entry{obj: new(int)}, shardCap, nil)
+	if p.nonEmpty != 1<<1|1<<14 {
+		t.Fatalf("Unexpected bitmap %b", p.nonEmpty)
+	}
+
+	tests := []struct {
+		shardID uint64
+		n       int
+		want    uint64
+	}{
+		{0, 4, 1 << 0},
+		{12, 4, 1 << 1},
+		{13, 16, 1<<0 | 1<<3},
+		{2, 4, 0},
+		{0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := p.stealCandidates(tt.shardID, tt.n); got != tt.want {
+			t.Errorf("stealCandidates(%d, %d) = %b, want %b", tt.shardID, tt.n, got, tt.want)
+		}
+	}
+}
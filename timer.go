@@ -0,0 +1,52 @@
+package pool
+
+import "time"
+
+// TimerPool is a pool of time.Timers. Reusing a timer is subtle: it must be
+// stopped and, if it fired and its value was never received, drained, or the
+// next user reads a stale expiry right after Reset. Put does both, so a timer
+// from Get never delivers a value of an earlier use.
+type TimerPool struct {
+	pool *Pool
+}
+
+// NewTimerPool creates a new timer pool.
+func NewTimerPool(opts ...Option) *TimerPool {
+	return &TimerPool{pool: NewPool(func() interface{} {
+		t := time.NewTimer(time.Hour)
+		stopTimer(t)
+		return t
+	}, opts...)}
+}
+
+// Get retrieves a timer that fires after d.
+func (tp *TimerPool) Get(d time.Duration) (*time.Timer, error) {
+	obj, err := tp.pool.GetE()
+	if err != nil {
+		return nil, err
+	}
+	t := obj.(*time.Timer)
+	t.Reset(d)
+	return t, nil
+}
+
+// Put stops and drains a timer and returns it to the pool. The timer must
+// come from Get and must not be used after Put; the caller may have received
+// its value or not.
+func (tp *TimerPool) Put(t *time.Timer) {
+	if t == nil {
+		return
+	}
+	stopTimer(t)
+	tp.pool.Put(t)
+}
+
+// stopTimer stops t and drops an expiry that was sent but never received.
+func stopTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+}
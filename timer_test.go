@@ -0,0 +1,38 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTimerPool tests that recycled timers never deliver a stale expiry.
+func TestTimerPool(t *testing.T) {
+	tp := NewTimerPool()
+	predictable(tp.pool)
+
+	timer, err := tp.Get(time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	tp.Put(timer)
+
+	reused, _ := tp.Get(time.Hour)
+	if reused != timer {
+		t.Error("Expected the timer to be reused")
+	}
+	select {
+	case <-reused.C:
+		t.Error("Expected no stale expiry from a recycled timer")
+	case <-time.After(20 * time.Millisecond):
+	}
+	tp.Put(reused)
+
+	timer, _ = tp.Get(time.Millisecond)
+	select {
+	case <-timer.C:
+	case <-time.After(time.Second):
+		t.Error("Expected the recycled timer to fire")
+	}
+	tp.Put(timer)
+}
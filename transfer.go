@@ -0,0 +1,75 @@
+package pool
+
+import "sync/atomic"
+
+// TransferTo moves up to n idle objects to other, e.g. to keep the warm objects
+// of a pool that is replaced by one with a new configuration, and returns the
+// number of objects moved.
+//
+// Each object is taken out of its shard and added to the same shard of other
+// as one step, so it is never handed out by both pools or lost: objects that
+// other does not retain, because it is full or closed, are pushed back into
+// the shard they came from. Stale objects, see Invalidate, are destroyed
+// instead of moved. Objects keep their metadata and priority but not their
+// shard hint.
+func (p *Pool) TransferTo(other *Pool, n int) int {
+	if other == p || n <= 0 {
+		return 0
+	}
+	cfg := p.config()
+	moved := 0
+	for i := range p.shards {
+		if moved >= n {
+			break
+		}
+		shard := &p.shards[i]
+		for _, e := range shard.popN(n-moved, cfg) {
+			if e.epoch < atomic.LoadUint64(&p.epoch) {
+				p.removed(e)
+				p.destroy(e.obj, cfg)
+				continue
+			}
+			if other.adopt(e, shardHint{key: uint64(i), set: true}) {
+				p.removed(e)
+				moved++
+				continue
+			}
+			shard.pushBack(e, cfg)
+		}
+	}
+	return moved
+}
+
+// pushBack returns an entry popped from the shard to its cold tier, regardless
+// of its capacity, since the entry was accounted as idle all along.
+func (s *poolShard) pushBack(e entry, cfg *config) {
+	s.mu.Lock()
+	s.objs = append(s.objs, e)
+	if policy := pushPolicy(cfg); policy != nil {
+		policy.OnPut(e.info())
+	}
+	s.unlock()
+}
+
+// adopt adds an idle entry taken from another pool, or just created, to the
+// shard of hint and reports whether it was retained. Like every entry that
+// does not come from a Put, it is poisoned by restore if p sanitizes.
+func (p *Pool) adopt(e entry, hint shardHint) bool {
+	if atomic.LoadInt32(&p.state) != stateOpen {
+		return false
+	}
+	cfg := p.config()
+	e.epoch = atomic.LoadUint64(&p.epoch)
+	e.hint = hint
+	e.heated = 0
+	e.size = 0
+	if cfg.sizer != nil {
+		e.size = cfg.sizer(e.obj)
+	}
+	if !cfg.metadata {
+		e.meta = nil
+	} else if e.meta == nil {
+		e.meta = &objectMeta{epoch: e.epoch}
+	}
+	return p.restore(e, cfg)
+}
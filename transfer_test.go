@@ -0,0 +1,98 @@
+package pool
+
+import "testing"
+
+// TestTransferTo tests that idle objects move between pools without loss.
+func TestTransferTo(t *testing.T) {
+	newInt := func() interface{} {
+		return new(int)
+	}
+	src := predictable(NewPool(newInt))
+	dst := predictable(NewPool(newInt))
+	for i := 0; i < 5; i++ {
+		src.Put(new(int))
+	}
+
+	if n := src.TransferTo(dst, 3); n != 3 {
+		t.Errorf("Expected 3 objects to be moved, got %d", n)
+	}
+	if a, b := idleCount(src), idleCount(dst); a != 2 || b != 3 {
+		t.Errorf("Expected 2 and 3 idle objects, got %d and %d", a, b)
+	}
+	if n := src.TransferTo(src, 1); n != 0 {
+		t.Errorf("Expected no transfer to itself, got %d", n)
+	}
+}
+
+// TestTransferToFull tests that objects rejected by the target stay in the source.
+func TestTransferToFull(t *testing.T) {
+	newInt := func() interface{} {
+		return new(int)
+	}
+	src := predictable(NewPool(newInt))
+	dst := predictable(NewPool(newInt))
+	dst.updateConfig(func(c *config) {
+		c.shardCap = 0
+	})
+	for i := 0; i < 3; i++ {
+		src.Put(new(int))
+	}
+
+	if n := src.TransferTo(dst, 3); n != 0 {
+		t.Errorf("Expected nothing to be moved, got %d", n)
+	}
+	if n := idleCount(src); n != 3 {
+		t.Errorf("Expected the objects to be put back, got %d idle", n)
+	}
+}
+
+// TestTransferToShards tests transfers of more objects than one shard holds.
+func TestTransferToShards(t *testing.T) {
+	var destroyed int
+	newInt := func() interface{} {
+		return new(int)
+	}
+	src := predictable(NewPool(newInt, WithDestructor(func(obj interface{}) { destroyed++ })))
+	dst := predictable(NewPool(newInt, WithShardCap(50)))
+	for i := 0; i < shardCount*100; i++ {
+		putShard(src, uint64(i), new(int))
+	}
+
+	if n := src.TransferTo(dst, shardCount*100); n != shardCount*50 {
+		t.Errorf("Expected %d objects to be moved, got %d", shardCount*50, n)
+	}
+	if a, b := idleCount(src), idleCount(dst); a != shardCount*50 || b != shardCount*50 {
+		t.Errorf("Expected %d idle objects in both pools, got %d and %d", shardCount*50, a, b)
+	}
+	if destroyed != 0 {
+		t.Errorf("Expected no object to be destroyed, got %d", destroyed)
+	}
+}
+
+// TestTransferToSanitized tests that objects moved into a sanitized pool are handed out without a report.
+func TestTransferToSanitized(t *testing.T) {
+	newBytes := func() interface{} {
+		return make([]byte, 8)
+	}
+	reports := 0
+	src := predictable(NewPool(newBytes))
+	src.updateConfig(func(c *config) {
+		c.sanitizeHandler = nil
+	})
+	dst := predictable(NewPool(newBytes, WithSanitizer(func(obj interface{}) {
+		reports++
+	})))
+	for i := 0; i < 3; i++ {
+		src.Put(make([]byte, 8))
+	}
+
+	if n := src.TransferTo(dst, 3); n != 3 {
+		t.Fatalf("Expected 3 objects to be moved, got %d", n)
+	}
+	for i := 0; i < 3; i++ {
+		dst.Get()
+	}
+	if reports != 0 {
+		t.Errorf("Expected no use-after-Put report, got %d", reports)
+	}
+}
@@ -0,0 +1,57 @@
+package pool
+
+// Trim destroys idle objects so that every shard keeps at most keepPerShard,
+// starting with the least recently returned ones (or as chosen by the eviction
+// policy). It returns the number of destroyed objects.
+func (p *Pool) Trim(keepPerShard int) int {
+	if keepPerShard < 0 {
+		keepPerShard = 0
+	}
+	cfg := p.config()
+	n := 0
+	for i := range p.shards {
+		n += p.trimShard(i, keepPerShard, cfg)
+	}
+	return n
+}
+
+// trimShard destroys idle objects so that shard i keeps at most keep, and
+// returns the number of destroyed objects.
+func (p *Pool) trimShard(i, keep int, cfg *config) int {
+	shard := &p.shards[i]
+	shard.mu.Lock()
+	shard.coolAll()
+	victims := shard.trim(len(shard.objs)-keep, cfg)
+	victims = append(victims, shard.retier(cfg)...)
+	shard.unlock()
+	for _, e := range victims {
+		p.evicted(e, cfg)
+	}
+	return len(victims)
+}
+
+// ShrinkToFit releases the unused capacity of the shards' backing arrays,
+// e.g. after a burst was followed by Trim.
+func (p *Pool) ShrinkToFit() {
+	for i := range p.shards {
+		shard := &p.shards[i]
+		shard.mu.Lock()
+		shard.objs = shrink(shard.objs)
+		shard.hot = shrink(shard.hot)
+		shard.soft = shrink(shard.soft)
+		shard.unlock()
+	}
+}
+
+// shrink returns objs in a backing array without unused capacity.
+func shrink(objs []entry) []entry {
+	if cap(objs) == len(objs) {
+		return objs
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+	shrunk := make([]entry, len(objs))
+	copy(shrunk, objs)
+	return shrunk
+}
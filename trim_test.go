@@ -0,0 +1,48 @@
+package pool
+
+import "testing"
+
+// TestTrim tests that Trim keeps at most the given number of objects per shard.
+func TestTrim(t *testing.T) {
+	destroyed := 0
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}, WithDestructor(func(obj interface{}) {
+		destroyed++
+	})))
+	for i := 0; i < 5; i++ {
+		p.Put(new(int))
+	}
+
+	if n := p.Trim(2); n != 3 || destroyed != 3 {
+		t.Errorf("Expected 3 objects to be trimmed and destroyed, got %d and %d", n, destroyed)
+	}
+	if n := idleCount(p); n != 2 {
+		t.Errorf("Expected 2 idle objects, got %d", n)
+	}
+	if n := p.Trim(2); n != 0 {
+		t.Errorf("Expected nothing to trim, got %d", n)
+	}
+}
+
+// TestShrinkToFit tests that ShrinkToFit releases unused capacity.
+func TestShrinkToFit(t *testing.T) {
+	p := predictable(NewPool(func() interface{} {
+		return new(int)
+	}))
+	for i := 0; i < 100; i++ {
+		p.Put(new(int))
+	}
+	p.Trim(1)
+	p.ShrinkToFit()
+
+	for i := range p.shards {
+		shard := &p.shards[i]
+		if cap(shard.objs) != len(shard.objs) {
+			t.Errorf("Expected shard %d to be shrunk, got len %d cap %d", i, len(shard.objs), cap(shard.objs))
+		}
+	}
+	if n := idleCount(p); n != 1 {
+		t.Errorf("Expected 1 idle object, got %d", n)
+	}
+}
@@ -0,0 +1,100 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate applies opts to the default configuration and reports the
+// settings that are incoherent, e.g. a memory cap without a Sizer, which
+// NewPool would accept and then silently ignore. The returned error joins one
+// error per problem, each matching ErrInvalidConfig; it is nil if there is
+// none.
+func Validate(opts ...Option) error {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg.validate(shardCount)
+}
+
+// Validate reports the incoherent settings of the current configuration of
+// the pool like the package-level Validate, e.g. as a startup self-check
+// after Reconfigure.
+func (p *Pool) Validate() error {
+	errs := []error{p.config().validate(len(p.shards))}
+	if n := len(p.shards); n == 0 || n&(n-1) != 0 {
+		errs = append(errs, invalid("shard count %d is not a power of two", n))
+	}
+	return errors.Join(errs...)
+}
+
+// invalid returns an error matching ErrInvalidConfig.
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidConfig, fmt.Sprintf(format, args...))
+}
+
+// validate returns the errors of the incoherent settings of c for a pool of
+// shards shards, joined.
+func (c *config) validate(shards int) error {
+	var errs []error
+	if c.shardCap < 0 {
+		errs = append(errs, invalid("WithShardCap(%d) is negative", c.shardCap))
+	}
+	if c.stealShardCnt < 0 {
+		errs = append(errs, invalid("WithStealShardCount(%d) is negative", c.stealShardCnt))
+	}
+	if c.maxInUse < 0 {
+		errs = append(errs, invalid("WithMaxInUse(%d) is negative", c.maxInUse))
+	}
+	if c.maxMemory < 0 {
+		errs = append(errs, invalid("WithMaxMemory(%d) is negative", c.maxMemory))
+	}
+	if c.maxMemory > 0 && c.sizer == nil {
+		errs = append(errs, invalid("WithMaxMemory(%d) has no effect without WithSizer", c.maxMemory))
+	}
+	if c.gcTrimFraction < 0 || c.gcTrimFraction > 1 {
+		errs = append(errs, invalid("WithGCTrim(%v) is not between 0 and 1", c.gcTrimFraction))
+	}
+	if c.leakTimeout < 0 {
+		errs = append(errs, invalid("WithLeakDetection(%v) is negative", c.leakTimeout))
+	}
+	if c.quarantine < 0 {
+		errs = append(errs, invalid("WithQuarantine(%v) is negative", c.quarantine))
+	}
+	if c.breakerFailures < 0 {
+		errs = append(errs, invalid("WithCircuitBreaker(%d, ...) is negative", c.breakerFailures))
+	}
+	if c.hotCap < 0 {
+		errs = append(errs, invalid("WithHotCold(%d, ...) is negative", c.hotCap))
+	}
+	if c.hotCap == 0 && c.hotAge > 0 {
+		errs = append(errs, invalid("WithHotCold demotion age %v has no hot tier to demote from", c.hotAge))
+	}
+	if c.softCap < 0 || c.softTTL < 0 {
+		errs = append(errs, invalid("WithSoftCap(%d, %v) is negative", c.softCap, c.softTTL))
+	}
+	if c.softCap == 0 && c.softTTL > 0 {
+		errs = append(errs, invalid("WithSoftCap ttl %v has no soft tier to decay", c.softTTL))
+	}
+	if c.refresh != nil && c.refreshInterval <= 0 {
+		errs = append(errs, invalid("WithRefresh(%v, ...) never runs without a positive interval", c.refreshInterval))
+	}
+	if c.refresh == nil && c.refreshInterval > 0 {
+		errs = append(errs, invalid("WithRefresh(%v, nil) has no refresh function", c.refreshInterval))
+	}
+	if c.retentionTarget < 0 {
+		errs = append(errs, invalid("WithRetentionTarget(%d) is negative", c.retentionTarget))
+	}
+	shardMax := c.shardCap
+	if c.autoTune != nil {
+		shardMax = c.autoTune.MaxCap
+	}
+	if capacity := shards * (shardMax + max(c.softCap, 0)); c.retentionTarget > capacity {
+		errs = append(errs, invalid("WithRetentionTarget(%d) exceeds the capacity of %d idle objects", c.retentionTarget, capacity))
+	}
+	if c.autoTune != nil && c.autoTune.MinCap < 0 {
+		errs = append(errs, invalid("WithAutoTune MinCap %d is negative", c.autoTune.MinCap))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,43 @@
+package pool
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestValidate tests that incoherent settings are reported.
+func TestValidate(t *testing.T) {
+	if err := Validate(WithShardCap(64), WithMaxMemory(1<<20), WithSizer(func(obj interface{}) int { return 1 })); err != nil {
+		t.Errorf("Expected a coherent configuration to pass, got %v", err)
+	}
+
+	err := Validate(
+		WithMaxMemory(1<<20),
+		WithGCTrim(2),
+		WithRefresh(0, func(obj interface{}) error { return nil }),
+		WithRetentionTarget(shardCount*shardCap+1),
+		WithSoftCap(0, time.Second),
+	)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Expected ErrInvalidConfig, got %v", err)
+	}
+	for _, want := range []string{"WithSizer", "WithGCTrim", "WithRefresh", "WithRetentionTarget", "WithSoftCap"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error about %s, got %v", want, err)
+		}
+	}
+}
+
+// TestPoolValidate tests the self-check of a running pool.
+func TestPoolValidate(t *testing.T) {
+	p := NewPool(func() interface{} { return new(int) })
+	if err := p.Validate(); err != nil {
+		t.Errorf("Expected the default pool to pass, got %v", err)
+	}
+	p.Reconfigure(WithMaxMemory(1024))
+	if err := p.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected the memory cap without a Sizer to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// weightTracker remembers the in-use units taken by the objects checked out
+// with GetWeighted, so their checkin releases as many.
+type weightTracker struct {
+	count   int64
+	mu      sync.Mutex
+	weights map[uintptr]int64
+}
+
+// checkout records that obj holds weight units.
+// It reports false if obj has no identity to track it by.
+func (t *weightTracker) checkout(obj interface{}, weight int64) bool {
+	id, ok := objectID(obj)
+	if !ok {
+		return false
+	}
+	t.mu.Lock()
+	if t.weights == nil {
+		t.weights = make(map[uintptr]int64)
+	}
+	if _, dup := t.weights[id]; !dup {
+		atomic.AddInt64(&t.count, 1)
+	}
+	t.weights[id] = weight
+	t.mu.Unlock()
+	return true
+}
+
+// checkin forgets obj and returns the number of units it holds.
+func (t *weightTracker) checkin(obj interface{}) int64 {
+	if atomic.LoadInt64(&t.count) == 0 {
+		return 1
+	}
+	id, ok := objectID(obj)
+	if !ok {
+		return 1
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	weight, ok := t.weights[id]
+	if !ok {
+		return 1
+	}
+	delete(t.weights, id)
+	atomic.AddInt64(&t.count, -1)
+	return weight
+}
+
+// weigh records the units held by an object checked out with GetWeighted.
+// Objects without an identity, such as values that are not pointers, cannot
+// be recognized on Put, so they keep a single unit and the rest is released.
+func (p *Pool) weigh(obj interface{}, weight int64) {
+	if !p.weights.checkout(obj, weight) {
+		p.inUse.release(weight - 1)
+	}
+}
+
+// GetWeighted retrieves an object like GetContext that takes weight units of
+// the in-use limit of WithMaxInUse instead of one, until it is returned with
+// Put or Discard, so a caller taking a huge buffer consumes more of the
+// budget than one taking a small buffer. Like a weighted semaphore, waiters
+// are served in order, so heavy Gets are not starved by light ones.
+// A weight above the limit fails at once with ErrWeightTooLarge; without a
+// limit the weight is ignored.
+func (p *Pool) GetWeighted(ctx context.Context, weight int) (interface{}, error) {
+	if weight < 1 {
+		weight = 1
+	}
+	if limit := p.config().maxInUse; limit > 0 && weight > limit {
+		return nil, fmt.Errorf("%w: weight %d, limit %d", ErrWeightTooLarge, weight, limit)
+	}
+	return p.getContext(ctx, getOptions{weight: int64(weight)})
+}
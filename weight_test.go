@@ -0,0 +1,60 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestGetWeighted tests that weighted objects hold their weight until Put.
+func TestGetWeighted(t *testing.T) {
+	p := NewPool(func() interface{} { return new([]byte) }, WithMaxInUse(10, true))
+	sem := p.Semaphore()
+
+	big, err := p.GetWeighted(context.Background(), 8)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := sem.Available(); n != 2 {
+		t.Errorf("Expected 2 free units, got %d", n)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.GetWeighted(ctx, 3); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected a weight above the free units to wait, got %v", err)
+	}
+	small, err := p.GetWeighted(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Expected the remaining units to be available, got %v", err)
+	}
+
+	p.Put(big)
+	if n := sem.Available(); n != 8 {
+		t.Errorf("Expected Put to release the weight, got %d free", n)
+	}
+	p.Discard(small)
+	if n := sem.Available(); n != 10 {
+		t.Errorf("Expected Discard to release the weight, got %d free", n)
+	}
+
+	if _, err := p.GetWeighted(context.Background(), 11); !errors.Is(err, ErrWeightTooLarge) {
+		t.Errorf("Expected ErrWeightTooLarge for a weight above the limit, got %v", err)
+	}
+}
+
+// TestGetWeightedValue tests objects that cannot be tracked keep a single unit.
+func TestGetWeightedValue(t *testing.T) {
+	p := NewPool(func() interface{} { return 1 }, WithMaxInUse(4, false))
+	obj, err := p.GetWeighted(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := p.Semaphore().Available(); n != 3 {
+		t.Errorf("Expected an untracked object to keep 1 unit, got %d free", n)
+	}
+	p.Put(obj)
+	if n := p.Semaphore().Available(); n != 4 {
+		t.Errorf("Expected all units to be free, got %d", n)
+	}
+}
@@ -0,0 +1,133 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// WorkerPool runs submitted tasks on a fixed set of reusable goroutines.
+// Each worker takes a scratch object from a Pool when it starts, passes it to
+// every task it runs and returns it when it exits, so tasks get per-worker
+// state without allocating or synchronizing.
+type WorkerPool struct {
+	pool    *Pool
+	workers int
+	tasks   chan func(scratch interface{})
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	running   int64
+	submitted uint64
+	completed uint64
+}
+
+// WorkerStats is a snapshot of the activity of a WorkerPool.
+type WorkerStats struct {
+	// Workers is the number of worker goroutines
+	Workers int `json:"workers"`
+	// Queued is the number of submitted tasks waiting for a worker
+	Queued int `json:"queued"`
+	// Running is the number of tasks being run
+	Running int64 `json:"running"`
+	// Submitted is the number of tasks accepted by Submit
+	Submitted uint64 `json:"submitted"`
+	// Completed is the number of tasks that finished
+	Completed uint64 `json:"completed"`
+}
+
+// NewWorkerPool starts workers goroutines taking their scratch objects from p,
+// with room for queue tasks waiting for a worker.
+// p may be nil for workers without scratch objects.
+func NewWorkerPool(p *Pool, workers, queue int) *WorkerPool {
+	if workers <= 0 {
+		panic("invalid worker count")
+	}
+	wp := &WorkerPool{
+		pool:    p,
+		workers: workers,
+		tasks:   make(chan func(scratch interface{}), max(queue, 0)),
+	}
+	wp.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go wp.work()
+	}
+	return wp
+}
+
+// work runs tasks until the queue is closed and drained.
+func (wp *WorkerPool) work() {
+	defer wp.wg.Done()
+	var scratch interface{}
+	if wp.pool != nil {
+		scratch = wp.pool.Get()
+		defer wp.pool.Put(scratch)
+	}
+	for task := range wp.tasks {
+		atomic.AddInt64(&wp.running, 1)
+		task(scratch)
+		atomic.AddInt64(&wp.running, -1)
+		atomic.AddUint64(&wp.completed, 1)
+	}
+}
+
+// Submit queues task to run on a worker, waiting while the queue is full.
+// It returns ErrPoolClosed after Shutdown.
+func (wp *WorkerPool) Submit(task func()) error {
+	return wp.SubmitScratch(func(scratch interface{}) {
+		task()
+	})
+}
+
+// SubmitScratch queues task to run on a worker with the worker's scratch
+// object, waiting while the queue is full. The scratch object is only valid
+// during the task and must not be Put by it.
+// It returns ErrPoolClosed after Shutdown.
+func (wp *WorkerPool) SubmitScratch(task func(scratch interface{})) error {
+	wp.mu.RLock()
+	defer wp.mu.RUnlock()
+	if wp.closed {
+		return ErrPoolClosed
+	}
+	wp.tasks <- task
+	atomic.AddUint64(&wp.submitted, 1)
+	return nil
+}
+
+// Shutdown stops accepting tasks and waits until the queued tasks have run and
+// the workers returned their scratch objects, or until ctx is done.
+// It returns ctx.Err() if ctx was done first; the workers then keep draining
+// the queue in the background.
+func (wp *WorkerPool) Shutdown(ctx context.Context) error {
+	wp.mu.Lock()
+	if !wp.closed {
+		wp.closed = true
+		close(wp.tasks)
+	}
+	wp.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		wp.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of the activity of the worker pool.
+func (wp *WorkerPool) Stats() WorkerStats {
+	return WorkerStats{
+		Workers:   wp.workers,
+		Queued:    len(wp.tasks),
+		Running:   atomic.LoadInt64(&wp.running),
+		Submitted: atomic.LoadUint64(&wp.submitted),
+		Completed: atomic.LoadUint64(&wp.completed),
+	}
+}
@@ -0,0 +1,62 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+// TestWorkerPool tests that tasks run with per-worker scratch objects.
+func TestWorkerPool(t *testing.T) {
+	created := int32(0)
+	p := NewPool(func() interface{} {
+		atomic.AddInt32(&created, 1)
+		return new(int)
+	})
+	wp := NewWorkerPool(p, 2, 4)
+
+	var sum int64
+	for i := 0; i < 100; i++ {
+		if err := wp.SubmitScratch(func(scratch interface{}) {
+			(*scratch.(*int))++
+			atomic.AddInt64(&sum, 1)
+		}); err != nil {
+			t.Fatalf("Unexpected Submit error %v", err)
+		}
+	}
+	if err := wp.Shutdown(context.Background()); err != nil {
+		t.Errorf("Unexpected Shutdown error %v", err)
+	}
+
+	if sum != 100 {
+		t.Errorf("Expected 100 tasks to run, got %d", sum)
+	}
+	if n := atomic.LoadInt32(&created); n != 2 {
+		t.Errorf("Expected 1 scratch object per worker, got %d", n)
+	}
+	if s := wp.Stats(); s.Submitted != 100 || s.Completed != 100 || s.Queued != 0 || s.Running != 0 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+	if err := wp.Submit(func() {}); err != ErrClosed {
+		t.Errorf("Expected ErrClosed after Shutdown, got %v", err)
+	}
+}
+
+// TestWorkerPoolShutdownTimeout tests that Shutdown gives up when ctx is done.
+func TestWorkerPoolShutdownTimeout(t *testing.T) {
+	wp := NewWorkerPool(nil, 1, 0)
+	release := make(chan struct{})
+	wp.Submit(func() {
+		<-release
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := wp.Shutdown(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	close(release)
+	if err := wp.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected the second Shutdown to wait for the task, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package pool
+
+import "bytes"
+
+// zero wipes the storage of obj, if it is a byte slice, *[]byte or
+// *bytes.Buffer, including the capacity beyond its length.
+// clear compiles to a memclr, so large buffers are wiped at memory speed.
+func zero(obj interface{}) {
+	switch o := obj.(type) {
+	case []byte:
+		clear(o[:cap(o)])
+	case *[]byte:
+		clear((*o)[:cap(*o)])
+	case *bytes.Buffer:
+		o.Reset()
+		b := o.Bytes()
+		clear(b[:cap(b)])
+	}
+}
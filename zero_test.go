@@ -0,0 +1,44 @@
+package pool
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestZeroOnPut tests that byte buffers are wiped before they are retained.
+func TestZeroOnPut(t *testing.T) {
+	// The sanitizer would poison the wiped storage
+	p := unsanitized(predictable(NewPool(func() interface{} {
+		return make([]byte, 0, 8)
+	}, WithZeroOnPut())))
+
+	b := append(p.Get().([]byte), "secret"...)
+	p.Put(b[:2])
+	if !bytes.Equal(b[:cap(b)], make([]byte, 8)) {
+		t.Errorf("Expected the whole capacity to be wiped, got %q", b[:cap(b)])
+	}
+
+	buf := bytes.NewBufferString("secret")
+	buf.Next(3)
+	zero(buf)
+	if raw := buf.Bytes(); buf.Len() != 0 || !bytes.Equal(raw[:cap(raw)], make([]byte, cap(raw))) {
+		t.Errorf("Expected the buffer to be reset and wiped, got %q", raw[:cap(raw)])
+	}
+}
+
+// TestZeroFunc tests that the custom zeroing func is called on Put.
+func TestZeroFunc(t *testing.T) {
+	type secret struct{ key string }
+	p := predictable(NewPool(func() interface{} {
+		return new(secret)
+	}, WithZeroFunc(func(obj interface{}) {
+		*obj.(*secret) = secret{}
+	})))
+
+	s := p.Get().(*secret)
+	s.key = "k"
+	p.Put(s)
+	if s.key != "" {
+		t.Error("Expected the object to be wiped")
+	}
+}